@@ -1,16 +1,21 @@
 // Package db returns an in-memory database to store metadata needed for the program
-// Right now it stores the forex exchange rates and the names <-> ticker mapping for symbols.
+// Right now it stores the forex exchange rates, the prices of shares needed for corporate actions
+// and the names <-> ticker mapping for symbols.
 // It is the caller's responsibility to call the Serialize function to persist changes to disk.
 package db
 
 import (
 	"fmt"
+
+	"aagr.xyz/trades/marketdata"
 )
 
-// InitDB is called by caller to initialize a database for symbols and forex.
-func InitDB(rootDir string) {
+// InitDB is called by caller to initialize a database for symbols, forex and prices.
+// md is used to look up the present price of a ticker, and can be nil.
+func InitDB(rootDir string, md *marketdata.Service) {
 	initSymbols(rootDir)
 	initForex(rootDir)
+	initPrices(rootDir, md)
 }
 
 // SerializeDB is called by caller to persist changes to disk
@@ -21,5 +26,8 @@ func SerializeDB(rootDir string) error {
 	if err := serializeForex(rootDir); err != nil {
 		return fmt.Errorf("cannot serialize forex: %v", err)
 	}
+	if err := serializePrices(rootDir); err != nil {
+		return fmt.Errorf("cannot serialize prices: %v", err)
+	}
 	return nil
 }
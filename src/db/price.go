@@ -0,0 +1,114 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"aagr.xyz/trades/config"
+	"aagr.xyz/trades/marketdata"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const pricesJSONFilename = "outputs/prices_db.json"
+
+var (
+	// prices stores the price in GBP of a share of a ticker on a given date
+	prices map[time.Time]map[string]float64
+	// market is used to get the price of a ticker on the present day
+	market *marketdata.Service
+)
+
+func initPrices(rootDir string, md *marketdata.Service) {
+	prices = make(map[time.Time]map[string]float64)
+	market = md
+	data, err := os.ReadFile(path.Join(rootDir, pricesJSONFilename))
+	if err != nil {
+		log.Errorf("Cannot read file for prices: %v", err)
+		return
+	}
+	err = json.Unmarshal(data, &prices)
+	if err != nil {
+		log.Errorf("Cannot unmarshal to struct: %v", err)
+		return
+	}
+}
+
+func serializePrices(rootDir string) error {
+	data, err := json.Marshal(prices)
+	if err != nil {
+		return fmt.Errorf("cannot marshal json: %v", err)
+	}
+	err = os.WriteFile(path.Join(rootDir, pricesJSONFilename), data, 0666)
+	if err != nil {
+		return fmt.Errorf("cannot write json file to disk: %v", err)
+	}
+	return nil
+}
+
+// AddPrice stores the price in GBP of a share of the ticker on the given date
+func AddPrice(ts time.Time, ticker string, value float64) {
+	date := ts.Truncate(24 * time.Hour)
+	if _, ok := prices[date]; !ok {
+		prices[date] = make(map[string]float64)
+	}
+	if _, ok := prices[date][ticker]; ok {
+		return
+	}
+	prices[date][ticker] = value
+}
+
+// GetPrice returns the price in GBP of a share of the ticker on the given date.
+// If it is not known, the present price from the market is used for the present day,
+// otherwise the user is asked to enter it.
+func GetPrice(ts time.Time, ticker string) (float64, error) {
+	date := ts.Truncate(24 * time.Hour)
+	if _, ok := prices[date]; !ok {
+		prices[date] = make(map[string]float64)
+	}
+	if val, ok := prices[date][ticker]; ok {
+		return val, nil
+	}
+	if date.Equal(time.Now().Truncate(24 * time.Hour)) {
+		val, err := presentPrice(ticker)
+		if err == nil {
+			prices[date][ticker] = val
+			return val, nil
+		}
+		log.Errorf("Cannot get present price of %s from the market: %v", ticker, err)
+	}
+	var inp float64
+	s := fmt.Sprintf("Price not known for date %v, 1 share of %s in GBP, please enter:", date.Format("2006-01-02"), ticker)
+	if config.Mode() == config.SERVER_MODE {
+		return 0.0, fmt.Errorf("%s", s)
+	}
+	fmt.Printf("%s\n", s)
+	fmt.Scanf("%f", &inp)
+	if inp <= 0.0 {
+		return 0.0, fmt.Errorf("invalid price %f for %s", inp, ticker)
+	}
+	prices[date][ticker] = inp
+	return inp, nil
+}
+
+func presentPrice(ticker string) (float64, error) {
+	if market == nil {
+		return 0.0, fmt.Errorf("no market data service")
+	}
+	meta, err := TickerMeta(ticker)
+	if err != nil {
+		return 0.0, err
+	}
+	quote, err := market.GetQuote(ticker, meta.Currency, meta.Metadata)
+	if err != nil {
+		return 0.0, err
+	}
+	forex, err := market.GetForex(meta.Currency)
+	if err != nil {
+		return 0.0, err
+	}
+	return quote.RegularMarketPrice * forex, nil
+}
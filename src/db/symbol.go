@@ -95,6 +95,24 @@ func SetCurrency(ticker string, currency record.Currency) error {
 	return nil
 }
 
+// SetAssetType sets the asset type of the ticker, adding it to the db if not present
+func SetAssetType(ticker string, assetType record.AssetType) {
+	if _, ok := symbols[ticker]; !ok {
+		symbols[ticker] = &Symbol{}
+	}
+	symbols[ticker].AssetType = assetType
+}
+
+// SetState sets the state of the ticker, an inactive ticker is not quoted in the market any more
+func SetState(ticker string, state SymbolState) error {
+	meta, ok := symbols[MostRecentTicker(ticker)]
+	if !ok {
+		return fmt.Errorf("ticker not added before")
+	}
+	meta.State = state
+	return nil
+}
+
 func insertTickerName(ticker string, name string) error {
 	if _, ok := symbols[ticker]; !ok {
 		symbols[ticker] = &Symbol{}
@@ -119,7 +137,7 @@ func insertTickerName(ticker string, name string) error {
 // TickerName returns the name of the ticker.
 // If it is not present, a new entry is created
 func TickerName(ticker string) (string, error) {
-	if _, ok := symbols[ticker]; !ok {
+	if s, ok := symbols[ticker]; !ok || len(s.Names) == 0 {
 		if err := insertTickerName(ticker, ""); err != nil {
 			return "", fmt.Errorf("cannot add ticker to db: %v", err)
 		}
@@ -225,14 +243,15 @@ func EnrichFromMarket(md *marketdata.Service) error {
 			continue
 		}
 		// Do not play around with forex
-		if meta.AssetType == record.FOREX_ASSET {
+		// There is no market data for derivatives either
+		if meta.AssetType == record.FOREX_ASSET || meta.AssetType.IsDerivative() {
 			continue
 		}
 		// If symbol is inactive, then ignore it.
 		if meta.State == Inactive {
 			continue
 		}
-		mds, err := md.Metadata(ticker, meta.Currency, meta.Metadata)
+		mds, err := md.Metadata(ticker, meta.Currency, meta.AssetType, meta.Metadata)
 		if err != nil {
 			return fmt.Errorf("cannot enrich from market: %v", err)
 		}
@@ -52,9 +52,16 @@ func ToActivities(recordsOrig []*record.Record) (*Activities, error) {
 		}
 		yticker := symbol.Metadata[marketdata.YAHOO].Ticker
 		switch r.Action {
-		case record.Unknown, record.Rename, record.Dividend, record.CashIn, record.CashOut:
+		case record.Unknown, record.Rename, record.Dividend, record.ExcessReportableIncome, record.CostAdjustment, record.Takeover, record.Demerger, record.NegligibleValue, record.SpouseTransferIn, record.ShareSchemeIncome, record.Interest, record.ClosedPosition, record.Exercise, record.CashIn, record.CashOut, record.PensionContribution, record.TaxRelief:
 			log.Warningf("Invalid type record: %v, skipping", r)
-		case record.Buy, record.Sell:
+		case record.Buy, record.Sell, record.RightsIssue, record.ScripDividend, record.CashInLieu, record.ESPPPurchase:
+			// shares acquired or disposed by a corporate action are a plain buy or sell for ghostfolio
+			switch r.Action {
+			case record.RightsIssue, record.ScripDividend, record.ESPPPurchase:
+				r.Action = record.Buy
+			case record.CashInLieu:
+				r.Action = record.Sell
+			}
 			a, err := toActivity(r, symbol)
 			if err != nil {
 				return nil, fmt.Errorf("cannot convert to activity %v: %v", r, err)
@@ -69,7 +76,7 @@ func ToActivities(recordsOrig []*record.Record) (*Activities, error) {
 			}
 		case record.TransferIn:
 			log.Infof("transfer in record %v is handled by it's transfer out", r)
-		case record.TransferOut:
+		case record.TransferOut, record.SpouseTransferOut:
 			acts, err := handleTransfer(r, symbol)
 			if err != nil {
 				return nil, fmt.Errorf("cannot handle transfer transaction: %v", err)
@@ -88,12 +95,10 @@ func ToActivities(recordsOrig []*record.Record) (*Activities, error) {
 }
 
 func handleSplit(activities []*Activity, description string) error {
-	var newCt, oldCt int64
-	_, err := fmt.Sscanf(description, "%d FOR %d", &newCt, &oldCt)
+	factor, err := record.ParseSplit(description)
 	if err != nil {
-		return fmt.Errorf("error in parsing transaction %s", description)
+		return fmt.Errorf("error in parsing transaction %s: %v", description, err)
 	}
-	factor := float64(newCt) / float64(oldCt)
 	for _, a := range activities {
 		a.Quantity *= factor
 		a.UnitPrice /= factor
@@ -124,6 +129,10 @@ func toActivity(r *record.Record, meta *db.Symbol) (*Activity, error) {
 		log.Warningf("Ignoring transaction for forex: %v", r)
 		return nil, nil
 	}
+	if meta.AssetType.IsDerivative() {
+		log.Warningf("Ignoring transaction for derivative: %v", r)
+		return nil, nil
+	}
 	a := &Activity{
 		Date:       r.Timestamp.Format(time.RFC3339),
 		DataSource: "YAHOO",
@@ -151,7 +160,7 @@ func toActivity(r *record.Record, meta *db.Symbol) (*Activity, error) {
 	if err != nil {
 		return nil, err
 	}
-	a.UnitPrice = r.PricePerShare * conversion
+	a.UnitPrice = r.PricePerShare * r.PriceMultiplier() * conversion
 	// Commission is always in GBP, so need to convert it first
 	// to record currency and then to yahoo currency
 	a.Fee = (r.Commission / r.ExchangeRate) * conversion
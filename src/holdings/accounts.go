@@ -13,10 +13,12 @@ type Account struct {
 	positions map[string]*position
 }
 
-func ByAccount(records []*record.Record) (map[record.Account]*Account, error) {
+// ByAccount returns the positions in every account. The transfers are the SPOUSETRANSFERIN records
+// returned by ByOwner, which add the shares to the account of the spouse.
+func ByAccount(records, transfers []*record.Record) (map[record.Account]*Account, error) {
 	var byAccount = make(map[record.Account][]*record.Record)
 	var globals []*record.Record
-	for _, r := range records {
+	for _, r := range append(append([]*record.Record{}, records...), transfers...) {
 		rCopy := *r
 		if r.Broker == record.GlobalBroker {
 			globals = append(globals, &rCopy)
@@ -51,12 +53,15 @@ func accountInternal(act record.Account, records []*record.Record) (*Account, er
 		positions: make(map[string]*position),
 	}
 	sortRecords(records, time.Second)
+	// the open lots of every future, whose profit or loss is settled when they are closed
+	futures := make(map[string][]*lot)
 	oldDate := records[0].Timestamp.Truncate(24 * time.Hour)
 	for _, r := range records {
 		// this is a new date transaction, so check if nothing is -ve
 		if !r.Timestamp.Truncate(24 * time.Hour).Equal(oldDate) {
 			for k, p := range a.positions {
-				if p.quantity < 0.0 {
+				// a contract can be sold to open a short position
+				if p.quantity < 0.0 && !isDerivative(k) {
 					return nil, fmt.Errorf("position %s became -ve on previous day %v: %f", k, oldDate, p.quantity)
 				}
 			}
@@ -72,6 +77,15 @@ func accountInternal(act record.Account, records []*record.Record) (*Account, er
 				return nil, fmt.Errorf("cannot cashin %s to account %v", r.Currency, act)
 			}
 			p.buy(r.ShareCount, r.ShareCount) // cash has price of 1.0
+		case record.PensionContribution, record.TaxRelief:
+			// These are the cash in of a SIPP, kept apart so that the tax relief is explained
+			if act.Type != record.SIPP_ACCOUNT {
+				return nil, fmt.Errorf("cannot add %s to account %v, which is not a SIPP", r.Action, act)
+			}
+			if act.Currency != record.MULTIPLE && act.Currency != r.Currency {
+				return nil, fmt.Errorf("cannot add %s in %s to account %v", r.Action, r.Currency, act)
+			}
+			p.buy(r.ShareCount, r.ShareCount)
 		case record.CashOut:
 			// If account is not multiple currency, then only cash out is same currency
 			if act.Currency != record.MULTIPLE && act.Currency != r.Currency {
@@ -86,29 +100,123 @@ func accountInternal(act record.Account, records []*record.Record) (*Account, er
 				return nil, fmt.Errorf("trying to transfer out %v, insufficient available quantity %f", r, p.quantity)
 			}
 			p.sell(r.ShareCount)
-		case record.TransferIn:
+		case record.TransferIn, record.SpouseTransferIn:
 			p.buy(r.ShareCount, r.Total/r.ExchangeRate)
-		case record.Dividend:
+		case record.SpouseTransferOut:
+			if p.quantity < r.ShareCount-epsilon {
+				return nil, fmt.Errorf("trying to transfer to spouse %v, insufficient available quantity %f", r, p.quantity)
+			}
+			p.sell(r.ShareCount)
+		case record.Dividend, record.CostAdjustment, record.Interest:
 			// If account is not multiple currency, then only dividend is same currency - treated as cash in
 			if act.Currency != record.MULTIPLE && act.Currency != r.Currency {
 				return nil, fmt.Errorf("cannot deposit dividend in currency %s to account %v", r.Currency, act)
 			}
 			a.positions[string(r.Currency)].buy(r.ShareCount, r.ShareCount)
+		case record.ExcessReportableIncome:
+			// ERI is not paid out to the account, it is only reported for tax
+		case record.NegligibleValue:
+			// The shares are still held, the claim only changes their cost for CGT
+		case record.ShareSchemeIncome:
+			// The income is only reported for tax, the shares kept are in a separate BUY
+		case record.ClosedPosition:
+			// Only the profit or loss of the position is settled in cash
+			if _, ok := a.positions[string(record.GBP)]; !ok {
+				a.positions[string(record.GBP)] = &position{}
+			}
+			if r.Total >= 0.0 {
+				a.positions[string(record.GBP)].buy(r.Total, r.Total)
+			} else {
+				a.positions[string(record.GBP)].sell(-r.Total)
+			}
 		case record.Buy:
 			p.buy(r.ShareCount, r.Total/r.ExchangeRate)
+			// only the variation margin of a future is settled, which is not a trade
+			if isFuture(r.Ticker) {
+				futures[r.Ticker] = settleFuture(a, futures[r.Ticker], r)
+				break
+			}
 			if err := sellOtherSide(r, a); err != nil {
 				return nil, fmt.Errorf("cannot sell otherside of buy: %v", err)
 			}
 		case record.Sell:
+			p.sell(r.ShareCount)
+			if isFuture(r.Ticker) {
+				futures[r.Ticker] = settleFuture(a, futures[r.Ticker], r)
+				break
+			}
+			buyOtherSide(r, a)
+		case record.Exercise:
+			// The option is closed without any cash, the underlying is traded in a separate record
+			if p.quantity > 0.0 {
+				p.sell(r.ShareCount)
+			} else {
+				p.buy(r.ShareCount, -p.averageCost()*r.ShareCount)
+			}
+		case record.RightsIssue:
+			p.buy(r.ShareCount, r.Total/r.ExchangeRate)
+			if err := sellOtherSide(r, a); err != nil {
+				return nil, fmt.Errorf("cannot pay for rights issue: %v", err)
+			}
+		case record.ESPPPurchase:
+			d, err := record.ParseESPP(r.Description)
+			if err != nil {
+				return nil, fmt.Errorf("error in parsing transaction %s: %v", r.String(), err)
+			}
+			p.buy(r.ShareCount, r.Total/r.ExchangeRate)
+			// only the discounted price is paid for the shares
+			paid := *r
+			paid.Total = r.ShareCount*d.PurchasePrice*r.ExchangeRate + r.Commission
+			if err := sellOtherSide(&paid, a); err != nil {
+				return nil, fmt.Errorf("cannot pay for ESPP purchase: %v", err)
+			}
+		case record.ScripDividend:
+			// the shares are received instead of cash, so no cash moves
+			p.buy(r.ShareCount, r.Total/r.ExchangeRate)
+		case record.CashInLieu:
+			if p.quantity < r.ShareCount-epsilon {
+				return nil, fmt.Errorf("trying to get cash in lieu for %v, insufficient available quantity %f", r, p.quantity)
+			}
 			p.sell(r.ShareCount)
 			buyOtherSide(r, a)
+		case record.Takeover:
+			t, err := record.ParseTakeover(r.Description)
+			if err != nil {
+				return nil, fmt.Errorf("error in parsing transaction %s: %v", r.String(), err)
+			}
+			if p.quantity <= epsilon {
+				break
+			}
+			if _, ok := a.positions[t.NewTicker]; !ok {
+				a.positions[t.NewTicker] = &position{}
+			}
+			a.positions[t.NewTicker].buy(p.quantity*t.NewCount/t.OldCount, p.totalCost*(1.0-t.CashFraction()))
+			if cash := p.quantity * t.CashPerShare; cash > epsilon {
+				if _, ok := a.positions[string(record.GBP)]; !ok {
+					a.positions[string(record.GBP)] = &position{}
+				}
+				a.positions[string(record.GBP)].buy(cash, cash)
+			}
+			p.quantity, p.totalCost = 0.0, 0.0
+		case record.Demerger:
+			d, err := record.ParseDemerger(r.Description)
+			if err != nil {
+				return nil, fmt.Errorf("error in parsing transaction %s: %v", r.String(), err)
+			}
+			if p.quantity <= epsilon {
+				break
+			}
+			if _, ok := a.positions[d.NewTicker]; !ok {
+				a.positions[d.NewTicker] = &position{}
+			}
+			a.positions[d.NewTicker].buy(p.quantity*d.NewCount/d.OldCount, p.totalCost*d.NewFraction())
+			p.totalCost *= 1.0 - d.NewFraction()
 		case record.Split:
-			var newCt, oldCt int64
-			_, err := fmt.Sscanf(r.Description, "%d FOR %d", &newCt, &oldCt)
+			ratio, err := record.ParseSplit(r.Description)
 			if err != nil {
-				return nil, fmt.Errorf("error in parsing transaction %s", r.String())
+				return nil, fmt.Errorf("error in parsing transaction %s: %v", r.String(), err)
 			}
-			p.split(newCt, oldCt)
+			p.split(ratio)
 		default:
 			return nil, fmt.Errorf("invalid record type: %v", r)
 		}
@@ -129,11 +237,12 @@ func sellOtherSide(r *record.Record, act *Account) error {
 	if act.Currency == record.MULTIPLE && r.Description == "" && r.Currency != record.GBP {
 		return nil
 	}
-	want := r.Total / r.ExchangeRate
+	// the accrued interest of a bond is paid on top of the total
+	want := (r.Total + r.AccruedInterest) / r.ExchangeRate
 	curr := string(r.Currency)
 	// if it is a a GBP account, or transaction is in GBP or currency conversion, make sure we have that much GBP available
 	if act.Currency == record.GBP || r.Description == "SELL GBP" {
-		want = r.Total
+		want = r.Total + r.AccruedInterest
 		curr = string(record.GBP)
 	}
 	available := act.positions[curr]
@@ -151,10 +260,10 @@ func buyOtherSide(r *record.Record, act *Account) {
 	if act.Currency == record.MULTIPLE && r.Description == "" && r.Currency != record.GBP {
 		return
 	}
-	got := r.Total / r.ExchangeRate
+	got := (r.Total + r.AccruedInterest) / r.ExchangeRate
 	curr := string(r.Currency)
 	if act.Currency == record.GBP || r.Description == "BUY GBP" {
-		got = r.Total
+		got = r.Total + r.AccruedInterest
 		curr = string(record.GBP)
 	}
 	if _, ok := act.positions[curr]; !ok {
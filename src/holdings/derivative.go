@@ -0,0 +1,249 @@
+package holdings
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+)
+
+// lot is an open position of a contract, whose quantity is negative for a short position
+type lot struct {
+	date     time.Time
+	quantity float64
+	// value per contract, which is the cost of a long position and the proceeds of a short one.
+	// It is in GBP for the pools, and in the currency of the contract for the accounts.
+	value float64
+}
+
+// isDerivative returns true if the ticker is an option or a future contract
+func isDerivative(ticker string) bool {
+	meta, err := db.TickerMeta(ticker)
+	return err == nil && (meta.AssetType == record.OPTION_ASSET || meta.AssetType == record.FUTURE_ASSET)
+}
+
+// isFuture returns true if the ticker is a future contract
+func isFuture(ticker string) bool {
+	meta, err := db.TickerMeta(ticker)
+	return err == nil && meta.AssetType == record.FUTURE_ASSET
+}
+
+// closeLots matches quantity contracts against the open lots first in first out. A positive quantity
+// closes long lots, and a negative one closes short lots. It returns the lots left and the ones closed.
+func closeLots(lots []*lot, quantity float64) ([]*lot, []*lot) {
+	var closed []*lot
+	for len(lots) > 0 && math.Abs(quantity) > epsilon && lots[0].quantity*quantity > 0.0 {
+		l := lots[0]
+		matched := math.Copysign(math.Min(math.Abs(l.quantity), math.Abs(quantity)), quantity)
+		closed = append(closed, &lot{date: l.date, quantity: matched, value: l.value})
+		l.quantity -= matched
+		quantity -= matched
+		if math.Abs(l.quantity) <= epsilon {
+			lots = lots[1:]
+		}
+	}
+	return lots, closed
+}
+
+// handleContractTrade matches a BUY or SELL of a contract against the open lots of the opposite direction,
+// adding a disposal for the lots closed. The quantity left opens a new lot.
+func handleContractTrade(poolActive *pool, lots []*lot, r *record.Record) ([]*lot, error) {
+	year := getTaxYear(r.Timestamp)
+	if year == "" {
+		return nil, fmt.Errorf("cannot calculate tax year from record timestamp: %v", r.Timestamp)
+	}
+	// a SELL closes long lots, and a BUY closes short ones
+	direction := 1.0
+	if r.Action == record.Buy {
+		direction = -1.0
+	}
+	lots, closed := closeLots(lots, direction*r.ShareCount)
+	unit := r.Total / r.ShareCount
+	d := &Disposal{Ticker: r.Ticker, Record: r, TaxYear: year}
+	for _, l := range closed {
+		qty := math.Abs(l.quantity)
+		proceeds, cost := qty*unit, qty*l.value
+		if l.quantity < 0.0 {
+			proceeds, cost = qty*l.value, qty*unit
+		}
+		// only the difference is settled for a future, so a profit is the proceeds and a loss is the cost
+		if isFuture(r.Ticker) {
+			proceeds, cost = math.Max(proceeds-cost, 0.0), math.Max(cost-proceeds, 0.0)
+		}
+		d.Matches = append(d.Matches, &Acquisition{
+			Rule:     ClosedPositionRule,
+			Date:     l.date,
+			Quantity: qty,
+			Cost:     cost,
+			Gain:     proceeds - cost,
+		})
+		d.Quantity += qty
+		d.Proceeds += proceeds
+		d.AllowableCost += cost
+		d.Gain += proceeds - cost
+	}
+	if len(d.Matches) > 0 {
+		poolActive.addDisposal(d)
+	}
+	if open := r.ShareCount - d.Quantity; open > epsilon {
+		lots = append(lots, &lot{date: r.Timestamp, quantity: -direction * open, value: unit})
+	}
+	return lots, nil
+}
+
+// settleFuture matches a BUY or SELL of a future against the open lots of the account, and settles the
+// profit or loss of the lots closed in the cash of the account. The quantity left opens a new lot.
+func settleFuture(act *Account, lots []*lot, r *record.Record) []*lot {
+	direction := 1.0
+	if r.Action == record.Buy {
+		direction = -1.0
+	}
+	lots, closed := closeLots(lots, direction*r.ShareCount)
+	unit := r.Total / r.ExchangeRate / r.ShareCount
+	var profit, quantity float64
+	for _, l := range closed {
+		profit += l.quantity * (unit - l.value)
+		quantity += math.Abs(l.quantity)
+	}
+	if open := r.ShareCount - quantity; open > epsilon {
+		lots = append(lots, &lot{date: r.Timestamp, quantity: -direction * open, value: unit})
+	}
+	if len(closed) == 0 {
+		return lots
+	}
+	curr := string(r.Currency)
+	if act.Currency == record.GBP {
+		profit *= r.ExchangeRate
+		curr = string(record.GBP)
+	}
+	if _, ok := act.positions[curr]; !ok {
+		act.positions[curr] = &position{}
+	}
+	if profit >= 0.0 {
+		act.positions[curr].buy(profit, profit)
+	} else {
+		act.positions[curr].sell(-profit)
+	}
+	return lots
+}
+
+// handleExercise closes the lots of an option exercised or assigned without any disposal, and returns the
+// record carrying the premium to the trade of the underlying, see applyExercises
+func handleExercise(lots []*lot, r *record.Record) ([]*lot, *record.Record, error) {
+	d, err := record.ParseExercise(r.Description)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot parse exercise: %v", err)
+	}
+	if len(lots) == 0 {
+		return nil, nil, fmt.Errorf("no open position of %s to exercise on %v", r.Ticker, r.Timestamp)
+	}
+	// only a long position is exercised, and only a short one is assigned
+	if d.Assigned != (lots[0].quantity < 0.0) {
+		return nil, nil, fmt.Errorf("cannot apply %s to the position of %f contracts of %s", d, lots[0].quantity, r.Ticker)
+	}
+	lots, closed := closeLots(lots, math.Copysign(r.ShareCount, lots[0].quantity))
+	// the premium paid for a long position is added to the cost, and the one received for a short
+	// position is taken off
+	var premium, quantity float64
+	for _, l := range closed {
+		premium += l.quantity * l.value
+		quantity += math.Abs(l.quantity)
+	}
+	if r.ShareCount-quantity > epsilon {
+		return nil, nil, fmt.Errorf("cannot exercise %f contracts of %s, only %f open", r.ShareCount, r.Ticker, quantity)
+	}
+	return lots, &record.Record{
+		Timestamp:    r.Timestamp,
+		Broker:       r.Broker,
+		Action:       record.Exercise,
+		Ticker:       d.Underlying,
+		Name:         r.Ticker,
+		ShareCount:   r.ShareCount * r.PriceMultiplier(),
+		Currency:     r.Currency,
+		ExchangeRate: r.ExchangeRate,
+		Total:        premium,
+		Description:  r.Description,
+	}, nil
+}
+
+// calculateDerivative calculates the holding of an option or a future contract, where every closing trade
+// is matched against the opening trades of the position instead of a pool
+func calculateDerivative(ticker string, recordsOrig []*record.Record) (*Holding, error) {
+	records, err := copyAndSortRecords(ticker, recordsOrig)
+	if err != nil {
+		return nil, fmt.Errorf("cannot copy and sort records based on timestamp: %v", err)
+	}
+	var (
+		taxable   = newPool()
+		cgtExempt = newPool()
+		lots      = make(map[*pool][]*lot)
+		carried   []*record.Record
+	)
+	for _, r := range records {
+		poolActive := taxable
+		if r.Broker.CGTExempt {
+			poolActive = cgtExempt
+		}
+		switch r.Action {
+		case record.Buy, record.Sell:
+			lots[poolActive], err = handleContractTrade(poolActive, lots[poolActive], r)
+			if err != nil {
+				return nil, fmt.Errorf("cannot handle %s: %v", r.Action, err)
+			}
+		case record.Exercise:
+			var c *record.Record
+			lots[poolActive], c, err = handleExercise(lots[poolActive], r)
+			if err != nil {
+				return nil, fmt.Errorf("cannot handle exercise: %v", err)
+			}
+			carried = append(carried, c)
+		default:
+			return nil, fmt.Errorf("invalid record type passed for contract: %v", r.Action)
+		}
+	}
+	return &Holding{
+		ticker:    ticker,
+		currency:  recordsOrig[0].Currency,
+		taxable:   taxable,
+		cgtExempt: cgtExempt,
+		records:   recordsOrig,
+		carried:   carried,
+	}, nil
+}
+
+// applyExercises adjusts the trade of the underlying delivered by an option exercise by the premium
+// carried from the option. The premium is part of the cost of the shares bought, or of the proceeds of
+// the shares sold. The trade is the one on the same day in the same account, in the direction of the
+// exercise and for the quantity of the contracts times their multiplier.
+func applyExercises(records []*record.Record) error {
+	for _, e := range records {
+		if e.Action != record.Exercise {
+			continue
+		}
+		d, err := record.ParseExercise(e.Description)
+		if err != nil {
+			return fmt.Errorf("cannot parse exercise of %s: %v", e.Name, err)
+		}
+		var trades []*record.Record
+		for _, r := range records {
+			if r.Action == d.Trade() && r.Timestamp.Equal(e.Timestamp) && r.Broker == e.Broker &&
+				math.Abs(r.ShareCount-e.ShareCount) <= epsilon {
+				trades = append(trades, r)
+			}
+		}
+		if len(trades) == 0 {
+			return fmt.Errorf("no %s of %f %s on %v for the exercise of %s", d.Trade(), e.ShareCount, e.Ticker, e.Timestamp, e.Name)
+		}
+		if len(trades) > 1 {
+			return fmt.Errorf("%d trades of %f %s on %v fit the exercise of %s, want 1", len(trades), e.ShareCount, e.Ticker, e.Timestamp, e.Name)
+		}
+		if trades[0].Action == record.Buy {
+			trades[0].Total += e.Total
+		} else {
+			trades[0].Total -= e.Total
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package holdings
+
+import (
+	"testing"
+	"time"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+)
+
+const (
+	testOption = "ABC 230721C00100000"
+	testFuture = "ESU3"
+)
+
+// contract returns a trade like trade, of contracts on 100 of the underlying
+func contract(ts time.Time, action record.TransactionType, ticker string, contracts, total float64) *record.Record {
+	r := trade(ts, action, ticker, contracts, total)
+	r.Multiplier = 100
+	r.PricePerShare /= r.Multiplier
+	return r
+}
+
+// exercise returns the EXERCISE of contracts of the option on ABC
+func exercise(ts time.Time, contracts float64, call, assigned bool) *record.Record {
+	return &record.Record{
+		Timestamp:    ts,
+		Broker:       testAccount,
+		Action:       record.Exercise,
+		Ticker:       testOption,
+		Name:         testOption,
+		ShareCount:   contracts,
+		Currency:     record.GBP,
+		ExchangeRate: 1.0,
+		Multiplier:   100,
+		Description:  (&record.ExerciseDetails{Underlying: "ABC", Call: call, Assigned: assigned}).String(),
+	}
+}
+
+func TestContractTrades(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	db.SetAssetType(testOption, record.OPTION_ASSET)
+	db.SetAssetType(testFuture, record.FUTURE_ASSET)
+	opened, closed := day(2023, time.June, 1, 10), day(2023, time.July, 3, 10)
+	for _, tc := range []struct {
+		name    string
+		records []*record.Record
+		want    []*Disposal
+	}{
+		{
+			name: "long",
+			records: []*record.Record{
+				contract(opened, record.Buy, testOption, 2, 1000),
+				contract(closed, record.Sell, testOption, 2, 1600),
+			},
+			want: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 2, Proceeds: 1600, AllowableCost: 1000, Gain: 600,
+				Matches: []*Acquisition{{Rule: ClosedPositionRule, Quantity: 2, Cost: 1000}},
+			}},
+		},
+		{
+			// The premium received for writing the option is the proceeds
+			name: "short",
+			records: []*record.Record{
+				contract(opened, record.Sell, testOption, 1, 500),
+				contract(closed, record.Buy, testOption, 1, 200),
+			},
+			want: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 1, Proceeds: 500, AllowableCost: 200, Gain: 300,
+				Matches: []*Acquisition{{Rule: ClosedPositionRule, Quantity: 1, Cost: 200}},
+			}},
+		},
+		{
+			// An option expiring worthless is closed at the price of 0
+			name: "expiry",
+			records: []*record.Record{
+				contract(opened, record.Buy, testOption, 1, 300),
+				contract(closed, record.Sell, testOption, 1, 0),
+			},
+			want: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 1, Proceeds: 0, AllowableCost: 300, Gain: -300,
+				Matches: []*Acquisition{{Rule: ClosedPositionRule, Quantity: 1, Cost: 300}},
+			}},
+		},
+		{
+			// Only the difference in the notional value of a future is settled
+			name: "future",
+			records: []*record.Record{
+				contract(opened, record.Buy, testFuture, 1, 100000),
+				contract(closed, record.Sell, testFuture, 1, 101000),
+			},
+			want: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 1, Proceeds: 1000, AllowableCost: 0, Gain: 1000,
+				Matches: []*Acquisition{{Rule: ClosedPositionRule, Quantity: 1, Cost: 0}},
+			}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			holdings, err := ByTicker(tc.records)
+			if err != nil {
+				t.Fatalf("ByTicker() failed: %v", err)
+			}
+			checkDisposals(t, holdings, tc.want)
+		})
+	}
+}
+
+func TestExercise(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	db.SetAssetType(testOption, record.OPTION_ASSET)
+	opened, exercised := day(2023, time.June, 1, 10), day(2023, time.July, 21, 10)
+	for _, tc := range []struct {
+		name    string
+		records []*record.Record
+		// want are the disposals and the pool of ABC left, with the 100 shares bought for 10000 on exercise
+		want                   []*Disposal
+		wantQuantity, wantCost float64
+	}{
+		{
+			// The premium paid for a call exercised is part of the cost of the shares
+			name: "call exercised",
+			records: []*record.Record{
+				contract(opened, record.Buy, testOption, 1, 500),
+				exercise(exercised, 1, true, false),
+			},
+			wantQuantity: 100, wantCost: 10500,
+		},
+		{
+			// The premium received for a put assigned is taken off the cost of the shares
+			name: "put assigned",
+			records: []*record.Record{
+				contract(opened, record.Sell, testOption, 1, 300),
+				exercise(exercised, 1, false, true),
+			},
+			wantQuantity: 100, wantCost: 9700,
+		},
+		{
+			// The premium only goes to the shares bought on exercise, not to a sale of the same day,
+			// which is matched against them
+			name: "another trade of the day",
+			records: []*record.Record{
+				trade(opened, record.Buy, "ABC", 50, 5000),
+				contract(opened, record.Buy, testOption, 1, 500),
+				trade(exercised.Add(-time.Hour), record.Sell, "ABC", 20, 2400),
+				exercise(exercised, 1, true, false),
+			},
+			want: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 20, Proceeds: 2400, AllowableCost: 2100, Gain: 300,
+				Matches: []*Acquisition{{Rule: SameDayRule, Quantity: 20, Cost: 2100}},
+			}},
+			wantQuantity: 130, wantCost: 13400,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			holdings, err := ByTicker(append(tc.records, trade(exercised, record.Buy, "ABC", 100, 10000)))
+			if err != nil {
+				t.Fatalf("ByTicker() failed: %v", err)
+			}
+			// The option is not disposed of
+			checkDisposals(t, holdings, tc.want)
+			if p := holdings["ABC"].taxable.gbp; !near(p.quantity, tc.wantQuantity) || !near(p.totalCost, tc.wantCost) {
+				t.Errorf("got pool %v, want qty=%f, totalCost=%.2f", p, tc.wantQuantity, tc.wantCost)
+			}
+		})
+	}
+}
+
+func TestSettleFuture(t *testing.T) {
+	opened, closed := day(2023, time.June, 1, 10), day(2023, time.July, 3, 10)
+	// usd returns a trade of the future in USD at 0.8 GBP, for the total in USD
+	usd := func(ts time.Time, action record.TransactionType, total float64) *record.Record {
+		r := contract(ts, action, testFuture, 1, total*0.8)
+		r.Currency, r.ExchangeRate = record.USD, 0.8
+		return r
+	}
+	for _, tc := range []struct {
+		name    string
+		records []*record.Record
+		// wantCash is the profit or loss settled in GBP
+		wantCash float64
+	}{
+		{
+			name: "long in GBP",
+			records: []*record.Record{
+				contract(opened, record.Buy, testFuture, 1, 100000),
+				contract(closed, record.Sell, testFuture, 1, 101000),
+			},
+			wantCash: 1000,
+		},
+		{
+			// The loss of 500 USD is settled at the exchange rate of the closing trade
+			name: "short in USD",
+			records: []*record.Record{
+				usd(opened, record.Sell, 10000),
+				usd(closed, record.Buy, 10500),
+			},
+			wantCash: -400,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			act := &Account{Account: testAccount, positions: make(map[string]*position)}
+			var lots []*lot
+			for _, r := range tc.records {
+				lots = settleFuture(act, lots, r)
+			}
+			if len(lots) != 0 {
+				t.Errorf("got open lots %v, want none", lots)
+			}
+			if p, ok := act.positions[string(record.GBP)]; !ok || !near(p.quantity, tc.wantCash) {
+				t.Errorf("got cash %v, want %.2f GBP", p, tc.wantCash)
+			}
+		})
+	}
+}
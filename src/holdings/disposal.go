@@ -0,0 +1,135 @@
+package holdings
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"aagr.xyz/trades/record"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// MatchRule is the HMRC share identification rule used to match a disposal with an acquisition
+type MatchRule string
+
+const (
+	SameDayRule         MatchRule = "SAME_DAY"
+	BedAndBreakfastRule MatchRule = "BED_AND_BREAKFAST"
+	PoolRule            MatchRule = "SECTION_104_POOL"
+	// ClosedPositionRule matches a closed spread bet, CFD, option or future position against its own opening
+	ClosedPositionRule MatchRule = "CLOSED_POSITION"
+)
+
+// Acquisition stores the part of a disposal matched against a single acquisition,
+// or against the section 104 pool.
+type Acquisition struct {
+	Rule MatchRule `json:"rule"`
+	// Date of the acquisition, not set when matched against the pool
+	Date     time.Time `json:"date,omitempty"`
+	Quantity float64   `json:"quantity"`
+	// Cost in GBP of the matched quantity
+	Cost float64 `json:"cost"`
+	Gain float64 `json:"gain"`
+}
+
+// Disposal stores how the gain of a SELL was calculated
+type Disposal struct {
+	Ticker   string         `json:"ticker"`
+	Record   *record.Record `json:"record"`
+	TaxYear  string         `json:"tax_year"`
+	Quantity float64        `json:"quantity"`
+	// Proceeds is before the commission, which is part of the allowable cost instead
+	Proceeds      float64        `json:"proceeds"`
+	AllowableCost float64        `json:"allowable_cost"`
+	Gain          float64        `json:"gain"`
+	Matches       []*Acquisition `json:"matches"`
+}
+
+func (d *Disposal) String() string {
+	return fmt.Sprintf("%s %s on %v, quantity %f, proceeds %.2f GBP, allowable cost %.2f GBP, gain %.2f GBP",
+		d.Record.Action, d.Ticker, d.Record.Timestamp.Format("2006-01-02"), d.Quantity, d.Proceeds, d.AllowableCost, d.Gain)
+}
+
+// addMatch matches quantity of the disposal against an acquisition with the given cost
+func (d *Disposal) addMatch(rule MatchRule, date time.Time, quantity, cost float64) {
+	gain := quantity*(d.Record.Total/d.Record.ShareCount) - cost
+	d.Matches = append(d.Matches, &Acquisition{
+		Rule:     rule,
+		Date:     date,
+		Quantity: quantity,
+		Cost:     cost,
+		Gain:     gain,
+	})
+	d.AllowableCost += cost
+	d.Gain += gain
+}
+
+// Disposals returns the ledger of disposals in taxable accounts, sorted by time
+func Disposals(holdings map[string]*Holding) []*Disposal {
+	var res []*Disposal
+	for _, h := range holdings {
+		res = append(res, h.taxable.disposals...)
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Record.Timestamp.Equal(res[j].Record.Timestamp) {
+			return res[i].Ticker < res[j].Ticker
+		}
+		return res[i].Record.Timestamp.Before(res[j].Record.Timestamp)
+	})
+	return res
+}
+
+// DisposalTable returns a table with a row for every acquisition matched against a disposal
+func DisposalTable(disposals []*Disposal) table.Writer {
+	t := table.NewWriter()
+	t.SetTitle("CGT Disposals")
+	t.SetStyle(table.StyleLight)
+	t.AppendHeader(table.Row{
+		"Date", "Ticker", "Account", "Tax Year", "Quantity", "Proceeds (GBP)", "Allowable Cost (GBP)", "Gain (GBP)",
+		"Rule", "Acquired On", "Matched Quantity", "Matched Cost (GBP)", "Matched Gain (GBP)",
+	})
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 6, Transformer: tf},
+		{Number: 7, Transformer: tf},
+		{Number: 8, Transformer: tf},
+		{Number: 12, Transformer: tf},
+		{Number: 13, Transformer: tf},
+	})
+	for _, d := range disposals {
+		for _, m := range d.Matches {
+			acquired := ""
+			if !m.Date.IsZero() {
+				acquired = m.Date.Format("2006-01-02")
+			}
+			t.AppendRow(table.Row{
+				d.Record.Timestamp.Format("2006-01-02"), d.Ticker, d.Record.Broker.Name, d.TaxYear,
+				d.Quantity, d.Proceeds, d.AllowableCost, d.Gain,
+				m.Rule, acquired, m.Quantity, m.Cost, m.Gain,
+			})
+		}
+	}
+	return t
+}
+
+// MatchTable returns a table with the acquisitions matched against a single disposal
+func MatchTable(d *Disposal) table.Writer {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleLight)
+	t.AppendHeader(table.Row{
+		"Rule", "Acquired On", "Quantity", "Cost (GBP)", "Gain (GBP)",
+	})
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 4, Transformer: tf},
+		{Number: 5, Transformer: tf},
+	})
+	for _, m := range d.Matches {
+		acquired := ""
+		if !m.Date.IsZero() {
+			acquired = m.Date.Format("2006-01-02")
+		}
+		t.AppendRow(table.Row{m.Rule, acquired, m.Quantity, m.Cost, m.Gain})
+	}
+	t.AppendFooter(table.Row{"Commission", "", "", tf(d.Record.Commission), ""})
+	t.AppendFooter(table.Row{"TOTAL", "", d.Quantity, tf(d.AllowableCost), tf(d.Gain)})
+	return t
+}
@@ -0,0 +1,71 @@
+package holdings
+
+import (
+	"aagr.xyz/trades/record"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// isFX returns true if the holding is of a foreign currency, like the cash left over from trades in USD
+func (h *Holding) isFX() bool {
+	return h.ticker == string(h.currency) && h.currency != record.GBP
+}
+
+// splitFX splits the holdings into the securities and the foreign currencies
+func splitFX(holdings map[string]*Holding) (map[string]*Holding, map[string]*Holding) {
+	securities, fx := make(map[string]*Holding), make(map[string]*Holding)
+	for ticker, h := range holdings {
+		if h.isFX() {
+			fx[ticker] = h
+		} else {
+			securities[ticker] = h
+		}
+	}
+	return securities, fx
+}
+
+// FXTables returns a table with the gains of every foreign currency for each tax year with a disposal.
+// Currency in accounts held for personal use is exempt, so it is not in the tables.
+func FXTables(holdings map[string]*Holding) map[string]table.Writer {
+	_, fx := splitFX(holdings)
+	totals := yearTotals(fx)
+	tables := gainTables(fx, "FX gains in tax year %s")
+	for ty := range tables {
+		if _, ok := totals[ty]; !ok {
+			delete(tables, ty)
+		}
+	}
+	return tables
+}
+
+// ReconciliationTable returns a table splitting the gain of every tax year between the securities and
+// the foreign currencies
+func ReconciliationTable(holdings map[string]*Holding) table.Writer {
+	securities, fx := splitFX(holdings)
+	totals, securitiesTotals, fxTotals := yearTotals(holdings), yearTotals(securities), yearTotals(fx)
+	t := table.NewWriter()
+	t.SetTitle("Gain Reconciliation")
+	t.SetStyle(table.StyleLight)
+	t.AppendHeader(table.Row{
+		"Tax Year", "Securities Gain (GBP)", "FX Gain (GBP)", "Total Gain (GBP)",
+	})
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 2, Transformer: tf},
+		{Number: 3, Transformer: tf},
+		{Number: 4, Transformer: tf},
+	})
+	years := maps.Keys(totals)
+	slices.Sort(years)
+	for _, ty := range years {
+		var securitiesGain, fxGain float64
+		if st, ok := securitiesTotals[ty]; ok {
+			securitiesGain = st.realizedGain
+		}
+		if st, ok := fxTotals[ty]; ok {
+			fxGain = st.realizedGain
+		}
+		t.AppendRow(table.Row{ty, securitiesGain, fxGain, totals[ty].realizedGain})
+	}
+	return t
+}
@@ -0,0 +1,179 @@
+package holdings
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/marketdata"
+	"aagr.xyz/trades/record"
+	log "github.com/sirupsen/logrus"
+)
+
+// HarvestRow is a position in the taxable pool along with its unrealised gain at present prices
+type HarvestRow struct {
+	Ticker   string
+	Currency string
+	Quantity float64
+	// PresentPrice is in the currency of the ticker, the rest are in GBP
+	PresentPrice   float64
+	AvgCost        float64
+	Value          float64
+	UnrealisedGain float64
+	// SuggestedQuantity is the quantity to sell to harvest SuggestedGain, which is negative for a loss
+	SuggestedQuantity, SuggestedGain float64
+	// RepurchaseIn are the ISAs where the ticker can be bought again right away
+	RepurchaseIn []string
+}
+
+// GainPerShare returns the gain in GBP realised by selling one share from the pool
+func (r *HarvestRow) GainPerShare() float64 {
+	return r.UnrealisedGain / r.Quantity
+}
+
+// HarvestPlan suggests disposals which use up the annual exempt amount of the present tax year,
+// or offset the gains already realised in it with losses.
+type HarvestPlan struct {
+	TaxYear            string
+	AnnualExemptAmount float64
+	// RealisedGain is the net gain of the present tax year so far
+	RealisedGain float64
+	// LossesAvailable are the losses brought forward from the previous years
+	LossesAvailable float64
+	// RemainingExempt is how much gain can still be realised without paying any CGT
+	RemainingExempt float64
+	// ExcessGain is the gain above the annual exempt amount after using the losses available
+	ExcessGain float64
+	Gains      []*HarvestRow
+	Losses     []*HarvestRow
+	// RepurchaseAfter is the first day a sold ticker can be bought in a taxable account
+	// without the bed and breakfast rule matching it against the suggested disposal.
+	RepurchaseAfter time.Time
+	// ExemptAccounts are the ISAs of the owner. The other CGT exempt accounts like a SIPP or a spread bet
+	// account are not meant for buying back the shares sold.
+	ExemptAccounts []string
+	Warnings       []string
+}
+
+// harvestRows returns the taxable positions split by whether they have an unrealised gain or loss.
+// The positions which cannot be valued at present are left out.
+func harvestRows(holdings map[string]*Holding, market *marketdata.Service) ([]*HarvestRow, []*HarvestRow) {
+	var gains, losses []*HarvestRow
+	for ticker, h := range holdings {
+		if h.taxable.gbp.quantity <= epsilon {
+			continue
+		}
+		quote, err := presentQuote(ticker, market)
+		if err != nil {
+			log.Errorf("Cannot get quote for %s, leaving it out of the harvest plan: %v", ticker, err)
+			continue
+		}
+		forex, err := presentForex(h.currency, market)
+		if err != nil {
+			log.Errorf("Cannot get forex for %s, leaving it out of the harvest plan: %v", ticker, err)
+			continue
+		}
+		r := &HarvestRow{
+			Ticker:       ticker,
+			Currency:     string(h.currency),
+			Quantity:     h.taxable.gbp.quantity,
+			PresentPrice: quote.RegularMarketPrice,
+			AvgCost:      h.taxable.gbp.averageCost(),
+			Value:        h.taxable.gbp.quantity * quote.RegularMarketPrice * forex.RegularMarketPrice,
+		}
+		r.UnrealisedGain = r.Value - h.taxable.gbp.totalCost
+		if r.UnrealisedGain > 0.0 {
+			gains = append(gains, r)
+		} else {
+			losses = append(losses, r)
+		}
+	}
+	// Largest gain per share first, so that the fewest shares are sold
+	sort.Slice(gains, func(i, j int) bool {
+		return gains[i].GainPerShare() > gains[j].GainPerShare()
+	})
+	sort.Slice(losses, func(i, j int) bool {
+		return losses[i].GainPerShare() < losses[j].GainPerShare()
+	})
+	return gains, losses
+}
+
+// isaEligible returns true if the ticker can be held in an ISA, which cannot hold cryptoassets or derivatives
+func isaEligible(ticker string) bool {
+	meta, err := db.TickerMeta(ticker)
+	if err != nil {
+		return false
+	}
+	return meta.AssetType != record.FOREX_ASSET && meta.AssetType != record.CRYPTO_ASSET && !meta.AssetType.IsDerivative()
+}
+
+// suggest fills in the quantity to sell from every row, in order, until the target gain is harvested
+func suggest(rows []*HarvestRow, target float64) {
+	for _, r := range rows {
+		if target <= epsilon {
+			return
+		}
+		perShare := math.Abs(r.GainPerShare())
+		if perShare <= epsilon {
+			continue
+		}
+		r.SuggestedQuantity = math.Min(r.Quantity, target/perShare)
+		r.SuggestedGain = r.SuggestedQuantity * r.GainPerShare()
+		target -= math.Abs(r.SuggestedGain)
+	}
+}
+
+// Harvest returns the plan for harvesting gains or losses in the present tax year.
+// accounts are used to find the ISAs to repurchase in.
+func Harvest(holdings map[string]*Holding, configs map[string]*TaxYearConfig, accounts map[record.Account]*Account, market *marketdata.Service) (*HarvestPlan, error) {
+	now := time.Now()
+	year := getTaxYear(now)
+	rules, err := rulesForTaxYear(year)
+	if err != nil {
+		return nil, err
+	}
+	plan := &HarvestPlan{
+		TaxYear:            year,
+		AnnualExemptAmount: rules.annualExemptAmount,
+		RepurchaseAfter:    now.AddDate(0, 0, 31),
+	}
+	if st, ok := yearTotals(holdings)[year]; ok {
+		plan.RealisedGain = st.realizedGain
+	}
+	_, losses := Liabilities(holdings, configs)
+	for _, l := range losses {
+		if l.TaxYear < year && !l.Expired(now) {
+			plan.LossesAvailable += l.Remaining()
+		}
+	}
+	for act := range accounts {
+		if act.Type == record.ISA_ACCOUNT {
+			plan.ExemptAccounts = append(plan.ExemptAccounts, act.Name)
+		}
+	}
+	sort.Strings(plan.ExemptAccounts)
+
+	plan.Gains, plan.Losses = harvestRows(holdings, market)
+	for _, r := range append(append([]*HarvestRow{}, plan.Gains...), plan.Losses...) {
+		if isaEligible(r.Ticker) {
+			r.RepurchaseIn = plan.ExemptAccounts
+		}
+	}
+	if plan.RealisedGain < plan.AnnualExemptAmount {
+		// A net loss realised this year is set off against the harvested gains first
+		plan.RemainingExempt = plan.AnnualExemptAmount - plan.RealisedGain
+		suggest(plan.Gains, plan.RemainingExempt)
+	} else {
+		plan.ExcessGain = math.Max(0.0, plan.RealisedGain-plan.AnnualExemptAmount-plan.LossesAvailable)
+		suggest(plan.Losses, plan.ExcessGain)
+	}
+	if len(plan.ExemptAccounts) == 0 {
+		plan.Warnings = append(plan.Warnings, "No ISA found, wait before buying back the tickers sold")
+	}
+	plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+		"Buying a sold ticker in a taxable account on or before %s matches it against the disposal under the bed and breakfast rule, which undoes the harvest",
+		now.AddDate(0, 0, 30).Format("2006-01-02")))
+	return plan, nil
+}
@@ -7,9 +7,11 @@ import (
 	"strings"
 	"time"
 
+	"aagr.xyz/trades/db"
 	"aagr.xyz/trades/record"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 const epsilon = 1e-5
@@ -36,19 +38,58 @@ func (p *position) buy(qty, cost float64) {
 	p.totalCost += cost
 }
 
+// addCost adds to the cost of the position without changing the quantity
+func (p *position) addCost(cost float64) {
+	p.totalCost += cost
+}
+
 func (p *position) sell(qty float64) {
 	average := p.averageCost()
 	p.quantity -= qty
 	p.totalCost -= average * qty
 }
 
-func (p *position) split(newCt, oldCt int64) {
-	ratio := float64(newCt) / float64(oldCt)
+// split scales the quantity by the ratio of new shares to old shares, the total cost is unchanged
+func (p *position) split(ratio float64) {
 	p.quantity *= ratio
 }
 
+// match stores the part of an acquisition that a disposal is matched against
+type match struct {
+	date           time.Time
+	quantity, cost float64
+}
+
 type stats struct {
 	realizedGain, disposed float64
+	// gains and losses store the sum of gains and losses of each disposal separately,
+	// as losses of a year have to be set off against the gains before carrying them forward.
+	gains, losses float64
+	// proceeds is the gross amount the assets were disposed for and allowableCost is
+	// the cost of acquiring them plus the incidental costs of disposal, as reported in SA108.
+	proceeds, allowableCost float64
+	disposals               int
+}
+
+// addGain adds the gain of a single disposal, negative gain being a loss
+func (s *stats) addGain(gain float64) {
+	s.realizedGain += gain
+	if gain > 0.0 {
+		s.gains += gain
+	} else {
+		s.losses -= gain
+	}
+}
+
+// add adds the stats of another pool in the same tax year
+func (s *stats) add(other *stats) {
+	s.disposed += other.disposed
+	s.realizedGain += other.realizedGain
+	s.gains += other.gains
+	s.losses += other.losses
+	s.proceeds += other.proceeds
+	s.allowableCost += other.allowableCost
+	s.disposals += other.disposals
 }
 
 // pool stores the position in both the base currency of the ticker
@@ -58,6 +99,8 @@ type pool struct {
 	// yearStats store the stats of this pool in a given tax year
 	// Since this computes gain and disposed amount, this is in GBP
 	yearStats map[string]*stats
+	// disposals is the ledger of every SELL from this pool
+	disposals []*Disposal
 }
 
 func (p *pool) String() string {
@@ -67,6 +110,20 @@ func (p *pool) String() string {
 	return fmt.Sprintf("base position = %s, gbp position = %s, yearlyStats = %v", p.base, p.gbp, p.yearStats)
 }
 
+// addDisposal adds the disposal to the ledger and the stats of its tax year
+func (p *pool) addDisposal(d *Disposal) {
+	if _, ok := p.yearStats[d.TaxYear]; !ok {
+		p.yearStats[d.TaxYear] = &stats{}
+	}
+	st := p.yearStats[d.TaxYear]
+	st.disposed += d.Proceeds - d.Record.Commission
+	st.addGain(d.Gain)
+	st.proceeds += d.Proceeds
+	st.allowableCost += d.AllowableCost
+	st.disposals++
+	p.disposals = append(p.disposals, d)
+}
+
 func newPool() *pool {
 	return &pool{
 		base:      &position{},
@@ -83,7 +140,12 @@ type Holding struct {
 	// the current pool of open position, split by taxable account and non-taxable
 	taxable   *pool
 	cgtExempt *pool
-	debug     string
+	// records the holding was calculated from
+	records []*record.Record
+	// carried are the records to be added to other tickers, like the shares received in a takeover
+	carried []*record.Record
+	// transfers are the records to be added to the spouse for the shares transferred to them
+	transfers []*record.Record
 }
 
 func sortRecords(records []*record.Record, truncate time.Duration) {
@@ -112,40 +174,130 @@ func copyAndSortRecords(ticker string, recordsOrig []*record.Record) ([]*record.
 	return records, nil
 }
 
+// aggregateSameDay merges all the buys and all the sells made on the same day in the same pool
+// into a single record each, as HMRC treats them as a single acquisition or disposal.
+// The records are expected to be sorted and truncated to the day.
+func aggregateSameDay(records []*record.Record) []*record.Record {
+	type key struct {
+		day       time.Time
+		action    record.TransactionType
+		cgtExempt bool
+	}
+	var (
+		res    []*record.Record
+		merged = make(map[key]*record.Record)
+	)
+	for _, r := range records {
+		if r.Action != record.Buy && r.Action != record.Sell {
+			res = append(res, r)
+			continue
+		}
+		k := key{day: r.Timestamp, action: r.Action, cgtExempt: r.Broker.CGTExempt}
+		m, ok := merged[k]
+		if !ok {
+			merged[k] = r
+			res = append(res, r)
+			continue
+		}
+		// the base currency total is kept intact by deriving the exchange rate from it
+		baseTotal := m.Total/m.ExchangeRate + r.Total/r.ExchangeRate
+		if qty := m.ShareCount + r.ShareCount; qty > 0.0 {
+			m.PricePerShare = (m.PricePerShare*m.ShareCount + r.PricePerShare*r.ShareCount) / qty
+		}
+		m.ShareCount += r.ShareCount
+		m.Commission += r.Commission
+		m.Total += r.Total
+		if baseTotal != 0.0 {
+			m.ExchangeRate = m.Total / baseTotal
+		}
+	}
+	return res
+}
+
+// matchSameDay matches each SELL against the BUY made on the same day in the same pool.
+// This is done upfront for all the records, as the same day rule takes priority over the
+// bed and breakfast rule i.e. a BUY is first matched against SELL of that day, and only
+// the remaining quantity can be matched against a SELL in the previous 30 days.
+// It returns the matched acquisition for every SELL and reduces the BUY records accordingly.
+func matchSameDay(records []*record.Record) map[*record.Record]*match {
+	res := make(map[*record.Record]*match)
+	for i, sell := range records {
+		if sell.Action != record.Sell {
+			continue
+		}
+		// Records are sorted, so all the BUY on this day are after the SELL
+		for j := i + 1; j < len(records) && records[j].Timestamp.Equal(sell.Timestamp); j++ {
+			buy := records[j]
+			if buy.Action != record.Buy || buy.Broker.CGTExempt != sell.Broker.CGTExempt {
+				continue
+			}
+			if math.Abs(buy.ShareCount-0.0) < epsilon {
+				continue
+			}
+			matched := math.Min(buy.ShareCount, sell.ShareCount)
+			cost := matched * (buy.Total / buy.ShareCount)
+			res[sell] = &match{date: buy.Timestamp, quantity: matched, cost: cost}
+			buy.ShareCount -= matched
+			buy.Total -= cost
+			break
+		}
+	}
+	return res
+}
+
 func handleSplit(taxable, isa *pool, r *record.Record) error {
-	var newCt, oldCt int64
-	_, err := fmt.Sscanf(r.Description, "%d FOR %d", &newCt, &oldCt)
+	ratio, err := record.ParseSplit(r.Description)
 	if err != nil {
-		return fmt.Errorf("error in parsing transaction %s", r.String())
+		return fmt.Errorf("error in parsing transaction %s: %v", r.String(), err)
 	}
 	// this will happen in both pools
-	taxable.base.split(newCt, oldCt)
-	taxable.gbp.split(newCt, oldCt)
-	isa.base.split(newCt, oldCt)
-	isa.gbp.split(newCt, oldCt)
+	taxable.base.split(ratio)
+	taxable.gbp.split(ratio)
+	isa.base.split(ratio)
+	isa.gbp.split(ratio)
 	return nil
 }
 
-func handleSell(poolActive *pool, records []*record.Record, presentIdx int, debug *strings.Builder) error {
+func handleSell(poolActive *pool, records []*record.Record, presentIdx int, sameDay *match) error {
 	r := records[presentIdx]
 	year := getTaxYear(r.Timestamp)
 	if year == "" {
 		return fmt.Errorf("cannot calculate tax year from record timestamp: %v", r.Timestamp)
 	}
-	if _, ok := poolActive.yearStats[year]; !ok {
-		poolActive.yearStats[year] = &stats{}
-	}
-	poolActive.yearStats[year].disposed += r.Total
 	toMatch := r.ShareCount
-	debug.WriteString(fmt.Sprintf("\nSELL on %v, quantity %f, price %f %s, total disposed %f GBP\n",
-		r.Timestamp.Format("2006-01-02"), toMatch, r.PricePerShare, r.Currency, r.Total))
+	d := &Disposal{
+		Ticker:   r.Ticker,
+		Record:   r,
+		TaxYear:  year,
+		Quantity: r.ShareCount,
+		// Total is net of the commission, which is an allowable cost of the disposal instead
+		Proceeds:      r.Total + r.Commission,
+		AllowableCost: r.Commission,
+	}
+
+	// First match this SELL with the acquisitions on the same day
+	if sameDay != nil {
+		d.addMatch(SameDayRule, sameDay.date, sameDay.quantity, sameDay.cost)
+		toMatch -= sameDay.quantity
+	}
 
-	// Now match this SELL with future transactions according to bed and breakfast rule
-	for j := presentIdx + 1; j < len(records) && toMatch > 0.0; j++ {
+	// Now match this SELL with future transactions according to bed and breakfast rule.
+	// ratio converts the shares sold to shares after any split in the 30 days.
+	ratio := 1.0
+	for j := presentIdx + 1; j < len(records) && toMatch > epsilon; j++ {
 		// greater than 30 days, so ignore and break, records is sorted
 		if records[j].Timestamp.Sub(r.Timestamp) > 30*24*time.Hour {
 			break
 		}
+		// a split applies to the shares of both the pools
+		if records[j].Action == record.Split {
+			splitRatio, err := record.ParseSplit(records[j].Description)
+			if err != nil {
+				return fmt.Errorf("error in parsing transaction %s: %v", records[j].String(), err)
+			}
+			ratio *= splitRatio
+			continue
+		}
 		// if sell and another transaction are not in same type, then skip
 		// both should be tax exempt or both not tax exempt
 		if r.Broker.CGTExempt != records[j].Broker.CGTExempt {
@@ -157,44 +309,304 @@ func handleSell(poolActive *pool, records []*record.Record, presentIdx int, debu
 			if math.Abs(records[j].ShareCount-0.0) < epsilon {
 				continue
 			}
-			matched := math.Min(records[j].ShareCount, toMatch)
+			matched := math.Min(records[j].ShareCount, toMatch*ratio)
 			// per share cost of selling - per share cost of buying.
 			cost := matched * (records[j].Total / records[j].ShareCount)
-			disposal := matched * (r.Total / r.ShareCount)
-			gain := disposal - cost
-			poolActive.yearStats[year].realizedGain += gain
-			debug.WriteString(fmt.Sprintf("\t\tMatched %f against BUY on %v, gain: %f GBP\n", matched, records[j].Timestamp.Format("2006-01-02"), gain))
+			d.addMatch(BedAndBreakfastRule, records[j].Timestamp, matched/ratio, cost)
 			records[j].ShareCount -= matched
 			records[j].Total -= cost
-			toMatch -= matched
+			toMatch -= matched / ratio
 		}
 	}
 	// if more shares are left to be matched, use the pool
-	if toMatch > 0 {
+	if toMatch > epsilon {
 		if poolActive.base.quantity < toMatch {
 			return fmt.Errorf("invalid quantity remanining in the pool, want %v, got %v", toMatch, poolActive.base.quantity)
 		}
-		cost := poolActive.gbp.averageCost() * toMatch
-		disposal := toMatch * (r.Total / r.ShareCount)
-		gain := disposal - cost
-		poolActive.yearStats[year].realizedGain += gain
-		debug.WriteString(fmt.Sprintf("\t\tMatched %f against POOL, with average cost %f, gain: %f GBP\n", toMatch, poolActive.gbp.averageCost(), gain))
+		d.addMatch(PoolRule, time.Time{}, toMatch, poolActive.gbp.averageCost()*toMatch)
 		poolActive.gbp.sell(toMatch)
 		poolActive.base.sell(toMatch)
 	}
+	poolActive.addDisposal(d)
+	return nil
+}
+
+// smallDistribution returns whether a capital distribution can be deducted from the cost instead of
+// being a part disposal, i.e. it is at most 5% of the value of the holding or at most £3000.
+// value is the market value of the holding after the distribution.
+func smallDistribution(amount, value float64) bool {
+	return amount <= 3000.0 || amount <= 0.05*(amount+value)
+}
+
+// handleCostAdjustment reduces the cost of the pool by a return of capital. A capital distribution which
+// is not small is a part disposal instead, with the cost apportioned by A/(A+B) where A is the distribution
+// and B is the market value of the remaining holding.
+func handleCostAdjustment(poolActive *pool, r *record.Record) error {
+	adj, err := record.ParseCostAdjustment(r.Description)
+	if err != nil {
+		return err
+	}
+	if poolActive.gbp.quantity <= epsilon {
+		return fmt.Errorf("no holding to adjust the cost of on %v", r.Timestamp)
+	}
+	year := getTaxYear(r.Timestamp)
+	if year == "" {
+		return fmt.Errorf("cannot calculate tax year from record timestamp: %v", r.Timestamp)
+	}
+	amount := r.Total
+	if !adj.Equalisation && !smallDistribution(amount, adj.MarketValue) {
+		if adj.MarketValue <= 0.0 {
+			return fmt.Errorf("market value needed for capital distribution of %f GBP, want DISTRIBUTION @ <market value>", amount)
+		}
+		fraction := amount / (amount + adj.MarketValue)
+		cost := poolActive.gbp.totalCost * fraction
+		poolActive.gbp.addCost(-cost)
+		poolActive.base.addCost(-poolActive.base.totalCost * fraction)
+		poolActive.addDisposal(costAdjustmentDisposal(r, year, amount, cost))
+		return nil
+	}
+	// The cost cannot go below 0, what is left over is a gain
+	reduce := math.Min(amount, poolActive.gbp.totalCost)
+	if poolActive.gbp.totalCost > 0.0 {
+		poolActive.base.addCost(-poolActive.base.totalCost * reduce / poolActive.gbp.totalCost)
+	}
+	poolActive.gbp.addCost(-reduce)
+	if amount-reduce > epsilon {
+		poolActive.addDisposal(costAdjustmentDisposal(r, year, amount-reduce, 0.0))
+	}
 	return nil
 }
 
+// handleTakeover exchanges the shares of the ticker for new shares and cash. For the shares of the ticker,
+// both pools are emptied and the records to carry the new shares to the pools of the new ticker are returned.
+// The cost is apportioned between the new shares and the cash by their market value, and the cash is a disposal.
+// For the new ticker, the carried records are added to the active pool.
+func handleTakeover(taxable, cgtExempt, poolActive *pool, r *record.Record, currency record.Currency) ([]*record.Record, error) {
+	t, err := record.ParseTakeover(r.Description)
+	if err != nil {
+		return nil, err
+	}
+	if r.Ticker == t.NewTicker {
+		handleCarried(poolActive, r)
+		return nil, nil
+	}
+	year := getTaxYear(r.Timestamp)
+	if year == "" {
+		return nil, fmt.Errorf("cannot calculate tax year from record timestamp: %v", r.Timestamp)
+	}
+	var carried []*record.Record
+	for _, p := range []*pool{taxable, cgtExempt} {
+		if p.gbp.quantity <= epsilon {
+			continue
+		}
+		newQty := p.gbp.quantity * t.NewCount / t.OldCount
+		cash := p.gbp.quantity * t.CashPerShare
+		cashCost := p.gbp.totalCost * t.CashFraction()
+		if cash > epsilon {
+			d := costAdjustmentDisposal(r, year, cash, cashCost)
+			d.Quantity = p.gbp.quantity
+			p.addDisposal(d)
+		}
+		if newQty > epsilon {
+			carried = append(carried, carriedRecord(r, p, p == cgtExempt, t.NewTicker, newQty, p.gbp.totalCost-cashCost, currency))
+		}
+		p.gbp.quantity, p.gbp.totalCost = 0.0, 0.0
+		p.base.quantity, p.base.totalCost = 0.0, 0.0
+	}
+	return carried, nil
+}
+
+// handleCashInLieu disposes the fractions of a share from the pool at its average cost.
+// It is a part disposal of the holding, so it is not matched against any acquisition.
+func handleCashInLieu(poolActive *pool, r *record.Record) error {
+	year := getTaxYear(r.Timestamp)
+	if year == "" {
+		return fmt.Errorf("cannot calculate tax year from record timestamp: %v", r.Timestamp)
+	}
+	if poolActive.gbp.quantity < r.ShareCount-epsilon {
+		return fmt.Errorf("cannot dispose %f shares for cash in lieu, only %f in the pool", r.ShareCount, poolActive.gbp.quantity)
+	}
+	d := &Disposal{
+		Ticker:        r.Ticker,
+		Record:        r,
+		TaxYear:       year,
+		Quantity:      r.ShareCount,
+		Proceeds:      r.Total + r.Commission,
+		AllowableCost: r.Commission,
+	}
+	d.addMatch(PoolRule, time.Time{}, r.ShareCount, poolActive.gbp.averageCost()*r.ShareCount)
+	poolActive.gbp.sell(r.ShareCount)
+	poolActive.base.sell(r.ShareCount)
+	poolActive.addDisposal(d)
+	return nil
+}
+
+// handleSpouseTransfer removes the shares transferred to the spouse from the pool at their average cost,
+// so there is no gain and no loss, and returns the record to add them to the pool of the spouse.
+// The account of the returned record only has the name, which is in the Description of the transfer.
+func handleSpouseTransfer(poolActive *pool, r *record.Record, currency record.Currency) (*record.Record, error) {
+	if poolActive.gbp.quantity < r.ShareCount-epsilon {
+		return nil, fmt.Errorf("cannot transfer %f shares to spouse, only %f in the pool", r.ShareCount, poolActive.gbp.quantity)
+	}
+	in := carriedRecord(r, poolActive, r.Broker.CGTExempt, r.Ticker, r.ShareCount, poolActive.gbp.averageCost()*r.ShareCount, currency)
+	in.Action = record.SpouseTransferIn
+	in.Broker = record.Account{Name: r.Description}
+	in.Description = r.Broker.Name
+	poolActive.gbp.sell(r.ShareCount)
+	poolActive.base.sell(r.ShareCount)
+	return in, nil
+}
+
+// handleNegligibleValue deems the shares in both pools disposed and reacquired at the value of the claim,
+// so the loss is crystallised in the tax year of the claim. The shares are kept in the pools at their new cost.
+func handleNegligibleValue(taxable, cgtExempt *pool, r *record.Record) error {
+	value, err := record.ParseNegligibleValue(r.Description)
+	if err != nil {
+		return err
+	}
+	year := getTaxYear(r.Timestamp)
+	if year == "" {
+		return fmt.Errorf("cannot calculate tax year from record timestamp: %v", r.Timestamp)
+	}
+	for _, p := range []*pool{taxable, cgtExempt} {
+		if p.gbp.quantity <= epsilon {
+			continue
+		}
+		proceeds := p.gbp.quantity * value
+		d := costAdjustmentDisposal(r, year, proceeds, p.gbp.totalCost)
+		d.Quantity = p.gbp.quantity
+		d.Matches[0].Quantity = p.gbp.quantity
+		p.addDisposal(d)
+		if p.gbp.totalCost > 0.0 {
+			p.base.totalCost *= proceeds / p.gbp.totalCost
+		}
+		p.gbp.totalCost = proceeds
+	}
+	return nil
+}
+
+// handleDemerger moves part of the cost of both pools of the ticker to the new shares, apportioned by the
+// market values on the first day after the demerger, and returns the records to carry the new shares to the
+// pools of the new ticker. The shares of the ticker are kept. For the new ticker, the carried records are
+// added to the active pool.
+func handleDemerger(taxable, cgtExempt, poolActive *pool, r *record.Record, currency record.Currency) ([]*record.Record, error) {
+	d, err := record.ParseDemerger(r.Description)
+	if err != nil {
+		return nil, err
+	}
+	if r.Ticker == d.NewTicker {
+		handleCarried(poolActive, r)
+		return nil, nil
+	}
+	if !d.HasPrices() {
+		return nil, fmt.Errorf("share prices needed to apportion the cost are missing in demerger %q", r.Description)
+	}
+	var carried []*record.Record
+	for _, p := range []*pool{taxable, cgtExempt} {
+		if p.gbp.quantity <= epsilon {
+			continue
+		}
+		newQty := p.gbp.quantity * d.NewCount / d.OldCount
+		carried = append(carried, carriedRecord(r, p, p == cgtExempt, d.NewTicker, newQty, p.gbp.totalCost*d.NewFraction(), currency))
+		p.gbp.totalCost *= 1.0 - d.NewFraction()
+		p.base.totalCost *= 1.0 - d.NewFraction()
+	}
+	return carried, nil
+}
+
+// handleCarried adds the shares carried over from another ticker, which keep the acquisition date
+// of the shares they came from, to the pool
+func handleCarried(poolActive *pool, r *record.Record) {
+	poolActive.gbp.buy(r.ShareCount, r.Total)
+	poolActive.base.buy(r.ShareCount, r.Total/r.ExchangeRate)
+}
+
+// carriedRecord returns a record to carry quantity shares of ticker with the given cost in GBP
+// from the pool p to the pool of the same kind of ticker
+func carriedRecord(r *record.Record, p *pool, cgtExempt bool, ticker string, quantity, cost float64, currency record.Currency) *record.Record {
+	rate := 1.0
+	if p.base.totalCost > 0.0 {
+		rate = p.gbp.totalCost / p.base.totalCost
+	}
+	return &record.Record{
+		Timestamp:     r.Timestamp,
+		Broker:        record.Account{Name: r.Broker.Name, CGTExempt: cgtExempt, Owner: r.Broker.Owner},
+		Action:        r.Action,
+		Ticker:        ticker,
+		Name:          ticker,
+		ShareCount:    quantity,
+		PricePerShare: cost / quantity / rate,
+		Currency:      currency,
+		ExchangeRate:  rate,
+		Total:         cost,
+		Description:   r.Description,
+	}
+}
+
+// handleClosedPosition adds the profit or loss of a closed position as a disposal, which is matched
+// against the opening of the position instead of the pool
+func handleClosedPosition(poolActive *pool, r *record.Record) error {
+	year := getTaxYear(r.Timestamp)
+	if year == "" {
+		return fmt.Errorf("cannot calculate tax year from record timestamp: %v", r.Timestamp)
+	}
+	details, err := record.ParseClosedPosition(r.Description)
+	if err != nil {
+		return fmt.Errorf("cannot parse closed position: %v", err)
+	}
+	// a loss is an allowable cost without any proceeds
+	proceeds, cost := r.Total, 0.0
+	if r.Total < 0.0 {
+		proceeds, cost = 0.0, -r.Total
+	}
+	poolActive.addDisposal(&Disposal{
+		Ticker:        r.Ticker,
+		Record:        r,
+		TaxYear:       year,
+		Quantity:      r.ShareCount,
+		Proceeds:      proceeds,
+		AllowableCost: cost,
+		Gain:          r.Total,
+		Matches: []*Acquisition{
+			{Rule: ClosedPositionRule, Date: details.Opened, Quantity: r.ShareCount, Cost: cost, Gain: r.Total},
+		},
+	})
+	return nil
+}
+
+func costAdjustmentDisposal(r *record.Record, year string, proceeds, cost float64) *Disposal {
+	return &Disposal{
+		Ticker:        r.Ticker,
+		Record:        r,
+		TaxYear:       year,
+		Proceeds:      proceeds,
+		AllowableCost: cost,
+		Gain:          proceeds - cost,
+		Matches: []*Acquisition{
+			{Rule: PoolRule, Cost: cost, Gain: proceeds - cost},
+		},
+	}
+}
+
 func calculateInternal(ticker string, recordsOrig []*record.Record) (*Holding, error) {
+	if isDerivative(ticker) {
+		return calculateDerivative(ticker, recordsOrig)
+	}
 	records, err := copyAndSortRecords(ticker, recordsOrig)
 	if err != nil {
 		return nil, fmt.Errorf("cannot copy and sort records based on timestamp: %v", err)
 	}
+	if err := applyExercises(records); err != nil {
+		return nil, fmt.Errorf("cannot apply option exercises: %v", err)
+	}
+	records = aggregateSameDay(records)
+	sameDay := matchSameDay(records)
 
 	var (
 		taxable    = newPool()
 		cgtExempt  = newPool()
-		debug      = &strings.Builder{}
+		carried    []*record.Record
+		transfers  []*record.Record
 		poolActive *pool
 	)
 
@@ -216,9 +628,60 @@ func calculateInternal(ticker string, recordsOrig []*record.Record) (*Holding, e
 			poolActive.gbp.buy(r.ShareCount, r.Total)
 			poolActive.base.buy(r.ShareCount, r.Total/r.ExchangeRate)
 		case record.Sell:
-			if err := handleSell(poolActive, records, i, debug); err != nil {
+			if err := handleSell(poolActive, records, i, sameDay[r]); err != nil {
 				return nil, fmt.Errorf("cannot handle SELL: %v", err)
 			}
+		case record.RightsIssue:
+			// A rights issue is a reorganisation, so the shares are treated as acquired with the shares
+			// already held and go straight to the pool without being matched against any disposal.
+			poolActive.gbp.buy(r.ShareCount, r.Total)
+			poolActive.base.buy(r.ShareCount, r.Total/r.ExchangeRate)
+		case record.CashInLieu:
+			if err := handleCashInLieu(poolActive, r); err != nil {
+				return nil, fmt.Errorf("cannot handle cash in lieu: %v", err)
+			}
+		case record.SpouseTransferOut:
+			t, err := handleSpouseTransfer(poolActive, r, recordsOrig[0].Currency)
+			if err != nil {
+				return nil, fmt.Errorf("cannot handle spouse transfer: %v", err)
+			}
+			transfers = append(transfers, t)
+		case record.SpouseTransferIn:
+			handleCarried(poolActive, r)
+		case record.NegligibleValue:
+			if err := handleNegligibleValue(taxable, cgtExempt, r); err != nil {
+				return nil, fmt.Errorf("cannot handle negligible value claim: %v", err)
+			}
+		case record.Takeover:
+			c, err := handleTakeover(taxable, cgtExempt, poolActive, r, recordsOrig[0].Currency)
+			if err != nil {
+				return nil, fmt.Errorf("cannot handle takeover: %v", err)
+			}
+			carried = append(carried, c...)
+		case record.Demerger:
+			c, err := handleDemerger(taxable, cgtExempt, poolActive, r, recordsOrig[0].Currency)
+			if err != nil {
+				return nil, fmt.Errorf("cannot handle demerger: %v", err)
+			}
+			carried = append(carried, c...)
+		case record.ClosedPosition:
+			if err := handleClosedPosition(poolActive, r); err != nil {
+				return nil, fmt.Errorf("cannot handle closed position: %v", err)
+			}
+		case record.Exercise:
+			// the premium of the option is already in the trade of the day, see applyExercises
+		case record.CostAdjustment:
+			if err := handleCostAdjustment(poolActive, r); err != nil {
+				return nil, fmt.Errorf("cannot handle cost adjustment: %v", err)
+			}
+		case record.ExcessReportableIncome:
+			if poolActive.base.quantity <= epsilon {
+				log.Warningf("Ignoring ERI of %s as there is no holding on %v", ticker, r.Timestamp)
+				continue
+			}
+			// ERI is added to the cost of the pool on the fund distribution date
+			poolActive.gbp.addCost(r.Total)
+			poolActive.base.addCost(r.Total / r.ExchangeRate)
 		default:
 			return nil, fmt.Errorf("invalid record type passed: %v", r.Action)
 		}
@@ -228,7 +691,9 @@ func calculateInternal(ticker string, recordsOrig []*record.Record) (*Holding, e
 		currency:  recordsOrig[0].Currency,
 		taxable:   taxable,
 		cgtExempt: cgtExempt,
-		debug:     debug.String(),
+		records:   recordsOrig,
+		carried:   carried,
+		transfers: transfers,
 	}, nil
 }
 
@@ -248,38 +713,160 @@ func dividendBuyRec(div *record.Record) *record.Record {
 		ExchangeRate:  div.ExchangeRate,
 		Commission:    0.0,
 		Total:         div.Total,
-		Description:   fmt.Sprintf("buy for %s dividend", div.Ticker),
+		Description:   fmt.Sprintf("buy for %s %s", div.Ticker, strings.ToLower(div.Action.String())),
 	}
 }
 
 // ByTicker takes in the records and calculates the present holding situation based on a ticker
 func ByTicker(records []*record.Record) (map[string]*Holding, error) {
+	byTicker := groupByTicker(records)
+	keys, err := tickerOrder(byTicker)
+	if err != nil {
+		return nil, fmt.Errorf("cannot order the tickers: %v", err)
+	}
+	var holdings map[string]*Holding = make(map[string]*Holding)
+	for _, ticker := range keys {
+		if len(byTicker[ticker]) == 0 {
+			continue
+		}
+		holding, err := calculateInternal(ticker, byTicker[ticker])
+		if err != nil {
+			return nil, fmt.Errorf("cannot calcuate holding stats for ticker %s: %v", ticker, err)
+		}
+		holdings[ticker] = holding
+		for _, c := range holding.carried {
+			byTicker[c.Ticker] = append(byTicker[c.Ticker], c)
+		}
+	}
+	return holdings, nil
+}
+
+// tickerOrder returns the tickers sorted such that a ticker taken over or demerged comes before its new ticker,
+// as the records carried over from it are needed to calculate the new ticker.
+func tickerOrder(byTicker map[string][]*record.Record) ([]string, error) {
+	next := make(map[string][]string)
+	for ticker, records := range byTicker {
+		for _, r := range records {
+			switch r.Action {
+			case record.Takeover:
+				t, err := record.ParseTakeover(r.Description)
+				if err != nil {
+					return nil, fmt.Errorf("cannot parse takeover of %s: %v", ticker, err)
+				}
+				next[ticker] = append(next[ticker], t.NewTicker)
+			case record.Demerger:
+				d, err := record.ParseDemerger(r.Description)
+				if err != nil {
+					return nil, fmt.Errorf("cannot parse demerger of %s: %v", ticker, err)
+				}
+				next[ticker] = append(next[ticker], d.NewTicker)
+			case record.Exercise:
+				// the premium of an option is carried to the underlying
+				d, err := record.ParseExercise(r.Description)
+				if err != nil {
+					return nil, fmt.Errorf("cannot parse exercise of %s: %v", ticker, err)
+				}
+				next[ticker] = append(next[ticker], d.Underlying)
+			}
+		}
+	}
+	var keys = maps.Keys(byTicker)
+	for _, tickers := range next {
+		for _, t := range tickers {
+			if _, ok := byTicker[t]; !ok {
+				byTicker[t] = nil
+				keys = append(keys, t)
+			}
+		}
+	}
+	sort.Strings(keys)
+	// depth first search, where a ticker is added after all the tickers it goes to
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var res []string
+	var visit func(string) error
+	visit = func(ticker string) error {
+		switch state[ticker] {
+		case visiting:
+			return fmt.Errorf("takeovers or demergers of %s form a cycle", ticker)
+		case visited:
+			return nil
+		}
+		state[ticker] = visiting
+		for _, t := range next[ticker] {
+			if err := visit(t); err != nil {
+				return err
+			}
+		}
+		state[ticker] = visited
+		res = append(res, ticker)
+		return nil
+	}
+	for _, ticker := range keys {
+		if err := visit(ticker); err != nil {
+			return nil, err
+		}
+	}
+	slices.Reverse(res)
+	return res, nil
+}
+
+// exemptRecord returns the record in the CGT exempt pool if the asset is exempt from CGT, which is
+// a bond, foreign currency held for personal use or a spread bet
+func exemptRecord(r *record.Record) *record.Record {
+	if r == nil || r.Broker == record.GlobalBroker || r.Broker.CGTExempt {
+		return r
+	}
+	exempt := r.Broker.PersonalUseFX && r.Ticker == string(r.Currency)
+	if r.Action == record.ClosedPosition {
+		d, err := record.ParseClosedPosition(r.Description)
+		exempt = err == nil && d.Instrument == record.SPREAD_BET_ASSET
+	}
+	if meta, err := db.TickerMeta(r.Ticker); !exempt && (err != nil || !meta.AssetType.IsBond()) {
+		return r
+	}
+	rCopy := *r
+	rCopy.Broker.CGTExempt = true
+	return &rCopy
+}
+
+// groupByTicker returns the records relevant for the CGT calculation of every ticker
+func groupByTicker(records []*record.Record) map[string][]*record.Record {
 	var byTicker map[string][]*record.Record = make(map[string][]*record.Record)
 	for _, r := range records {
+		r = exemptRecord(r)
 		switch r.Action {
-		case record.Rename, record.TransferIn, record.TransferOut, record.CashIn, record.CashOut:
+		case record.Rename, record.TransferIn, record.TransferOut, record.CashIn, record.CashOut, record.ShareSchemeIncome,
+			record.PensionContribution, record.TaxRelief:
 			continue
-		case record.Dividend:
-			// Dividend in another currency is considered buy for that currency
-			buyR := dividendBuyRec(r)
+		case record.Dividend, record.Interest:
+			// Dividend or interest in another currency is considered buy for that currency
+			buyR := exemptRecord(dividendBuyRec(r))
+			if buyR != nil {
+				byTicker[buyR.Ticker] = append(byTicker[buyR.Ticker], buyR)
+			}
+		case record.CostAdjustment:
+			// The cash received in another currency is a buy of that currency, just like a dividend
+			byTicker[r.Ticker] = append(byTicker[r.Ticker], r)
+			buyR := exemptRecord(dividendBuyRec(r))
 			if buyR != nil {
 				byTicker[buyR.Ticker] = append(byTicker[buyR.Ticker], buyR)
 			}
+		case record.ESPPPurchase, record.ScripDividend:
+			// The shares are acquired on the day like any other BUY. The ESPP shares are acquired at the market
+			// value, the discount is employment income. A stock dividend is an acquisition on its own at the
+			// cash alternative, unlike the shares of a rights issue.
+			buyR := *r
+			buyR.Action = record.Buy
+			byTicker[r.Ticker] = append(byTicker[r.Ticker], &buyR)
 		case record.WitholdingTax:
 			log.Fatal(fmt.Errorf("invalid witholding tax record %v", r))
 		default:
 			byTicker[r.Ticker] = append(byTicker[r.Ticker], r)
 		}
 	}
-	var keys = maps.Keys(byTicker)
-	sort.Strings(keys)
-	var holdings map[string]*Holding = make(map[string]*Holding)
-	for _, ticker := range keys {
-		holding, err := calculateInternal(ticker, byTicker[ticker])
-		if err != nil {
-			log.Fatal(fmt.Errorf("cannot calcuate holding stats for ticker %s: %v", ticker, err))
-		}
-		holdings[ticker] = holding
-	}
-	return holdings, nil
+	return byTicker
 }
@@ -0,0 +1,505 @@
+package holdings
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"aagr.xyz/trades/record"
+)
+
+var testAccount = record.Account{Name: "GIA", Currency: record.GBP}
+
+// day returns the time on the given day at the hour, to order the records of a day
+func day(year int, month time.Month, d, hour int) time.Time {
+	return time.Date(year, month, d, hour, 0, 0, 0, time.UTC)
+}
+
+// trade returns a BUY or SELL in GBP of quantity shares of the ticker for total
+func trade(ts time.Time, action record.TransactionType, ticker string, quantity, total float64) *record.Record {
+	return &record.Record{
+		Timestamp:     ts,
+		Broker:        testAccount,
+		Action:        action,
+		Ticker:        ticker,
+		Name:          ticker,
+		ShareCount:    quantity,
+		PricePerShare: total / quantity,
+		Currency:      record.GBP,
+		ExchangeRate:  1.0,
+		Total:         total,
+	}
+}
+
+// corporate returns a global record of the ticker, like a split
+func corporate(ts time.Time, action record.TransactionType, ticker, desc string) *record.Record {
+	return &record.Record{
+		Timestamp:   ts,
+		Broker:      record.GlobalBroker,
+		Action:      action,
+		Ticker:      ticker,
+		Description: desc,
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
+
+// checkDisposals compares the disposals of the holdings against the wanted ones, looking only at the
+// fields used by HMRC to work out the gain
+func checkDisposals(t *testing.T, holdings map[string]*Holding, want []*Disposal) {
+	t.Helper()
+	got := Disposals(holdings)
+	if len(got) != len(want) {
+		t.Fatalf("got %d disposals, want %d: %v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.TaxYear != w.TaxYear || !near(g.Quantity, w.Quantity) || !near(g.Proceeds, w.Proceeds) ||
+			!near(g.AllowableCost, w.AllowableCost) || !near(g.Gain, w.Gain) {
+			t.Errorf("disposal %d: got %v in %s, want quantity %f, proceeds %.2f GBP, allowable cost %.2f GBP, gain %.2f GBP in %s",
+				i, g, g.TaxYear, w.Quantity, w.Proceeds, w.AllowableCost, w.Gain, w.TaxYear)
+		}
+		if len(g.Matches) != len(w.Matches) {
+			t.Errorf("disposal %d: got %d matches, want %d", i, len(g.Matches), len(w.Matches))
+			continue
+		}
+		for j, wm := range w.Matches {
+			gm := g.Matches[j]
+			if gm.Rule != wm.Rule || !near(gm.Quantity, wm.Quantity) || !near(gm.Cost, wm.Cost) {
+				t.Errorf("disposal %d match %d: got %s of %f for %.2f, want %s of %f for %.2f",
+					i, j, gm.Rule, gm.Quantity, gm.Cost, wm.Rule, wm.Quantity, wm.Cost)
+			}
+		}
+	}
+}
+
+func TestSameDay(t *testing.T) {
+	// 1000 shares are in the section 104 pool at 1 GBP each before every case
+	pool := trade(day(2023, time.January, 10, 10), record.Buy, "ABC", 1000, 1000)
+	for _, tc := range []struct {
+		name    string
+		records []*record.Record
+		want    []*Disposal
+	}{
+		{
+			// The two buys of the day are a single acquisition of 200 shares for 500, which is matched
+			// first, and the rest of the sale comes from the pool
+			name: "buys of the day are aggregated",
+			records: []*record.Record{
+				trade(day(2023, time.June, 1, 9), record.Buy, "ABC", 100, 200),
+				trade(day(2023, time.June, 1, 12), record.Sell, "ABC", 300, 1200),
+				trade(day(2023, time.June, 1, 15), record.Buy, "ABC", 100, 300),
+			},
+			want: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 300, Proceeds: 1200, AllowableCost: 600, Gain: 600,
+				Matches: []*Acquisition{
+					{Rule: SameDayRule, Quantity: 200, Cost: 500},
+					{Rule: PoolRule, Quantity: 100, Cost: 100},
+				},
+			}},
+		},
+		{
+			// The two sales of the day are a single disposal of 300 shares for 1500
+			name: "sales of the day are aggregated",
+			records: []*record.Record{
+				trade(day(2023, time.June, 1, 9), record.Sell, "ABC", 100, 400),
+				trade(day(2023, time.June, 1, 12), record.Buy, "ABC", 150, 450),
+				trade(day(2023, time.June, 1, 15), record.Sell, "ABC", 200, 1100),
+			},
+			want: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 300, Proceeds: 1500, AllowableCost: 600, Gain: 900,
+				Matches: []*Acquisition{
+					{Rule: SameDayRule, Quantity: 150, Cost: 450},
+					{Rule: PoolRule, Quantity: 150, Cost: 150},
+				},
+			}},
+		},
+		{
+			// The same day rule comes before the bed and breakfast rule, which comes before the pool
+			name: "same day before bed and breakfast",
+			records: []*record.Record{
+				trade(day(2023, time.June, 1, 12), record.Sell, "ABC", 300, 1200),
+				trade(day(2023, time.June, 1, 15), record.Buy, "ABC", 100, 200),
+				trade(day(2023, time.June, 10, 10), record.Buy, "ABC", 100, 250),
+			},
+			want: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 300, Proceeds: 1200, AllowableCost: 550, Gain: 650,
+				Matches: []*Acquisition{
+					{Rule: SameDayRule, Quantity: 100, Cost: 200},
+					{Rule: BedAndBreakfastRule, Quantity: 100, Cost: 250},
+					{Rule: PoolRule, Quantity: 100, Cost: 100},
+				},
+			}},
+		},
+		{
+			// A buy more than 30 days after the sale goes to the pool
+			name: "no bed and breakfast after 30 days",
+			records: []*record.Record{
+				trade(day(2023, time.June, 1, 12), record.Sell, "ABC", 500, 1000),
+				trade(day(2023, time.July, 2, 10), record.Buy, "ABC", 500, 2000),
+			},
+			want: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 500, Proceeds: 1000, AllowableCost: 500, Gain: 500,
+				Matches: []*Acquisition{
+					{Rule: PoolRule, Quantity: 500, Cost: 500},
+				},
+			}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			holdings, err := ByTicker(append([]*record.Record{pool}, tc.records...))
+			if err != nil {
+				t.Fatalf("ByTicker() failed: %v", err)
+			}
+			checkDisposals(t, holdings, tc.want)
+		})
+	}
+}
+
+func TestBedAndBreakfastAcrossSplit(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		split string
+		// bought is the quantity bought back after the split, for 2200
+		bought float64
+		// poolQuantity is the quantity left in the pool at the end, which is the 700 shares not matched
+		// after the split, for 7000
+		poolQuantity float64
+	}{
+		// 400 shares bought after a 2 for 1 split are 200 of the shares sold
+		{name: "split", split: "2 FOR 1", bought: 400, poolQuantity: 1400},
+		// 20 shares bought after a 1 for 10 reverse split are 200 of the shares sold
+		{name: "reverse split", split: "1 FOR 10", bought: 20, poolQuantity: 70},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			holdings, err := ByTicker([]*record.Record{
+				trade(day(2023, time.January, 10, 10), record.Buy, "ABC", 1000, 10000),
+				trade(day(2023, time.June, 1, 10), record.Sell, "ABC", 500, 6000),
+				corporate(day(2023, time.June, 10, 0), record.Split, "ABC", tc.split),
+				trade(day(2023, time.June, 20, 10), record.Buy, "ABC", tc.bought, 2200),
+			})
+			if err != nil {
+				t.Fatalf("ByTicker() failed: %v", err)
+			}
+			// The quantities matched are in the shares sold, before the split
+			checkDisposals(t, holdings, []*Disposal{{
+				TaxYear: "2023-24", Quantity: 500, Proceeds: 6000, AllowableCost: 5200, Gain: 800,
+				Matches: []*Acquisition{
+					{Rule: BedAndBreakfastRule, Quantity: 200, Cost: 2200},
+					{Rule: PoolRule, Quantity: 300, Cost: 3000},
+				},
+			}})
+			p := holdings["ABC"].taxable.gbp
+			if !near(p.quantity, tc.poolQuantity) || !near(p.totalCost, 7000) {
+				t.Errorf("got pool %v, want qty=%f, totalCost=7000", p, tc.poolQuantity)
+			}
+		})
+	}
+}
+
+func TestSmallDistribution(t *testing.T) {
+	for _, tc := range []struct {
+		amount, value float64
+		want          bool
+	}{
+		{amount: 3000, value: 10000, want: true},
+		{amount: 3001, value: 10000, want: false},
+		// 5% of the value before the distribution, which is 5200
+		{amount: 4000, value: 100000, want: true},
+		{amount: 5000, value: 95000, want: true},
+		{amount: 6000, value: 100000, want: false},
+	} {
+		if got := smallDistribution(tc.amount, tc.value); got != tc.want {
+			t.Errorf("smallDistribution(%.0f, %.0f) = %v, want %v", tc.amount, tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestCapitalDistribution(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		// cost of the 1000 shares in the pool
+		cost   float64
+		amount float64
+		desc   string
+		// poolCost is the cost of the pool after the distribution
+		poolCost float64
+		want     []*Disposal
+	}{
+		{
+			name: "small by amount", cost: 20000, amount: 2500, desc: "DISTRIBUTION @ 100000",
+			poolCost: 17500,
+		},
+		{
+			name: "small by value", cost: 20000, amount: 4000, desc: "DISTRIBUTION @ 100000",
+			poolCost: 16000,
+		},
+		{
+			name: "equalisation is never a disposal", cost: 20000, amount: 6000, desc: "EQUALISATION",
+			poolCost: 14000,
+		},
+		{
+			// The cost is apportioned by A/(A+B), i.e. 6000/106000 of 20000
+			name: "part disposal", cost: 20000, amount: 6000, desc: "DISTRIBUTION @ 100000",
+			poolCost: 18867.92,
+			want: []*Disposal{{
+				TaxYear: "2023-24", Proceeds: 6000, AllowableCost: 1132.08, Gain: 4867.92,
+				Matches: []*Acquisition{{Rule: PoolRule, Cost: 1132.08}},
+			}},
+		},
+		{
+			// The cost cannot go below nil, the rest of the distribution is a gain
+			name: "small above the cost", cost: 2000, amount: 2500, desc: "DISTRIBUTION @ 100000",
+			poolCost: 0,
+			want: []*Disposal{{
+				TaxYear: "2023-24", Proceeds: 500, AllowableCost: 0, Gain: 500,
+				Matches: []*Acquisition{{Rule: PoolRule, Cost: 0}},
+			}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			holdings, err := ByTicker([]*record.Record{
+				trade(day(2023, time.January, 10, 10), record.Buy, "ABC", 1000, tc.cost),
+				{
+					Timestamp:     day(2023, time.June, 1, 10),
+					Broker:        testAccount,
+					Action:        record.CostAdjustment,
+					Ticker:        "ABC",
+					ShareCount:    tc.amount,
+					PricePerShare: 1.0,
+					Currency:      record.GBP,
+					ExchangeRate:  1.0,
+					Total:         tc.amount,
+					Description:   tc.desc,
+				},
+			})
+			if err != nil {
+				t.Fatalf("ByTicker() failed: %v", err)
+			}
+			checkDisposals(t, holdings, tc.want)
+			if p := holdings["ABC"].taxable.gbp; !near(p.quantity, 1000) || !near(p.totalCost, tc.poolCost) {
+				t.Errorf("got pool %v, want qty=1000, totalCost=%.2f", p, tc.poolCost)
+			}
+		})
+	}
+}
+
+func TestTakeover(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		desc string
+		// want are the disposals of the old ticker, and the new ticker is sold for 9000 at the end
+		want, wantNew []*Disposal
+	}{
+		{
+			// The new shares take the cost of the old ones, so nothing is disposed of at the takeover
+			name: "shares only", desc: "XYZ 2 FOR 1",
+			wantNew: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 2000, Proceeds: 9000, AllowableCost: 10000, Gain: -1000,
+				Matches: []*Acquisition{{Rule: PoolRule, Quantity: 2000, Cost: 10000}},
+			}},
+		},
+		{
+			// The cash of 3000 is a fifth of the consideration worth 15000, so it takes a fifth of the cost
+			name: "shares and cash", desc: "XYZ 1 FOR 1 CASH 3 @ 12",
+			want: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 1000, Proceeds: 3000, AllowableCost: 2000, Gain: 1000,
+				Matches: []*Acquisition{{Rule: PoolRule, Cost: 2000}},
+			}},
+			wantNew: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 1000, Proceeds: 9000, AllowableCost: 8000, Gain: 1000,
+				Matches: []*Acquisition{{Rule: PoolRule, Quantity: 1000, Cost: 8000}},
+			}},
+		},
+		{
+			// The whole holding is disposed of for cash
+			name: "cash only", desc: "XYZ 0 FOR 1 CASH 12",
+			want: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 1000, Proceeds: 12000, AllowableCost: 10000, Gain: 2000,
+				Matches: []*Acquisition{{Rule: PoolRule, Cost: 10000}},
+			}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			records := []*record.Record{
+				trade(day(2023, time.January, 10, 10), record.Buy, "ABC", 1000, 10000),
+				corporate(day(2023, time.June, 1, 0), record.Takeover, "ABC", tc.desc),
+			}
+			if tc.wantNew != nil {
+				records = append(records, trade(day(2023, time.September, 1, 10), record.Sell, "XYZ", tc.wantNew[0].Quantity, 9000))
+			}
+			holdings, err := ByTicker(records)
+			if err != nil {
+				t.Fatalf("ByTicker() failed: %v", err)
+			}
+			if p := holdings["ABC"].taxable.gbp; !near(p.quantity, 0) || !near(p.totalCost, 0) {
+				t.Errorf("got pool %v of the old ticker, want it empty", p)
+			}
+			checkDisposals(t, map[string]*Holding{"ABC": holdings["ABC"]}, tc.want)
+			if tc.wantNew != nil {
+				checkDisposals(t, map[string]*Holding{"XYZ": holdings["XYZ"]}, tc.wantNew)
+			}
+		})
+	}
+}
+
+func TestDemerger(t *testing.T) {
+	isa := trade(day(2023, time.February, 1, 10), record.Buy, "ABC", 400, 6000)
+	isa.Broker = record.Account{Name: "ISA", Currency: record.GBP, CGTExempt: true}
+	// An old share is worth 8 and a new one 4 after the demerger, so the new shares received for
+	// two old ones take a fifth of their cost
+	holdings, err := ByTicker([]*record.Record{
+		trade(day(2023, time.January, 10, 10), record.Buy, "ABC", 1000, 10000),
+		isa,
+		corporate(day(2023, time.June, 1, 0), record.Demerger, "ABC", "XYZ 1 FOR 2 @ 8 4"),
+		trade(day(2023, time.September, 1, 10), record.Sell, "XYZ", 500, 2500),
+	})
+	if err != nil {
+		t.Fatalf("ByTicker() failed: %v", err)
+	}
+	for _, tc := range []struct {
+		name                   string
+		got                    *position
+		wantQuantity, wantCost float64
+	}{
+		{name: "old ticker", got: holdings["ABC"].taxable.gbp, wantQuantity: 1000, wantCost: 8000},
+		{name: "old ticker in ISA", got: holdings["ABC"].cgtExempt.gbp, wantQuantity: 400, wantCost: 4800},
+		{name: "new ticker", got: holdings["XYZ"].taxable.gbp, wantQuantity: 0, wantCost: 0},
+		{name: "new ticker in ISA", got: holdings["XYZ"].cgtExempt.gbp, wantQuantity: 200, wantCost: 1200},
+	} {
+		if !near(tc.got.quantity, tc.wantQuantity) || !near(tc.got.totalCost, tc.wantCost) {
+			t.Errorf("%s: got pool %v, want qty=%f, totalCost=%f", tc.name, tc.got, tc.wantQuantity, tc.wantCost)
+		}
+	}
+	// The demerger itself is not a disposal
+	checkDisposals(t, map[string]*Holding{"ABC": holdings["ABC"]}, nil)
+	checkDisposals(t, map[string]*Holding{"XYZ": holdings["XYZ"]}, []*Disposal{{
+		TaxYear: "2023-24", Quantity: 500, Proceeds: 2500, AllowableCost: 2000, Gain: 500,
+		Matches: []*Acquisition{{Rule: PoolRule, Quantity: 500, Cost: 2000}},
+	}})
+}
+
+func TestRightsIssueAndScripDividend(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		records []*record.Record
+		want    []*Disposal
+		// the pool left at the end
+		wantQuantity, wantCost float64
+	}{
+		{
+			// The shares of a rights issue are acquired with the shares already held, so they are
+			// not matched against a sale of the 30 days before
+			name: "rights issue",
+			records: []*record.Record{
+				trade(day(2023, time.June, 1, 10), record.Sell, "ABC", 500, 6000),
+				trade(day(2023, time.June, 10, 10), record.RightsIssue, "ABC", 250, 2000),
+			},
+			want: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 500, Proceeds: 6000, AllowableCost: 5000, Gain: 1000,
+				Matches: []*Acquisition{{Rule: PoolRule, Quantity: 500, Cost: 5000}},
+			}},
+			wantQuantity: 750, wantCost: 7000,
+		},
+		{
+			// Unlike a rights issue, the shares of a scrip dividend are a new acquisition
+			name: "scrip dividend after a sale",
+			records: []*record.Record{
+				trade(day(2023, time.June, 1, 10), record.Sell, "ABC", 500, 6000),
+				trade(day(2023, time.June, 10, 10), record.ScripDividend, "ABC", 20, 220),
+			},
+			want: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 500, Proceeds: 6000, AllowableCost: 5020, Gain: 980,
+				Matches: []*Acquisition{
+					{Rule: BedAndBreakfastRule, Quantity: 20, Cost: 220},
+					{Rule: PoolRule, Quantity: 480, Cost: 4800},
+				},
+			}},
+			wantQuantity: 520, wantCost: 5200,
+		},
+		{
+			// The cash alternative is the cost of the shares received
+			name: "scrip dividend",
+			records: []*record.Record{
+				trade(day(2023, time.June, 10, 10), record.ScripDividend, "ABC", 20, 220),
+			},
+			wantQuantity: 1020, wantCost: 10220,
+		},
+		{
+			// The cash for half a share is a part disposal at the average cost of the pool
+			name: "cash in lieu",
+			records: []*record.Record{
+				trade(day(2023, time.June, 10, 10), record.CashInLieu, "ABC", 0.5, 6),
+			},
+			want: []*Disposal{{
+				TaxYear: "2023-24", Quantity: 0.5, Proceeds: 6, AllowableCost: 5, Gain: 1,
+				Matches: []*Acquisition{{Rule: PoolRule, Quantity: 0.5, Cost: 5}},
+			}},
+			wantQuantity: 999.5, wantCost: 9995,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			holdings, err := ByTicker(append([]*record.Record{
+				trade(day(2023, time.January, 10, 10), record.Buy, "ABC", 1000, 10000),
+			}, tc.records...))
+			if err != nil {
+				t.Fatalf("ByTicker() failed: %v", err)
+			}
+			checkDisposals(t, holdings, tc.want)
+			if p := holdings["ABC"].taxable.gbp; !near(p.quantity, tc.wantQuantity) || !near(p.totalCost, tc.wantCost) {
+				t.Errorf("got pool %v, want qty=%f, totalCost=%f", p, tc.wantQuantity, tc.wantCost)
+			}
+		})
+	}
+}
+
+func TestNegligibleValue(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		desc string
+		// want are the disposals of the claim and of the sale of the shares for 800 afterwards
+		want []*Disposal
+	}{
+		{
+			name: "nil value", desc: "",
+			want: []*Disposal{
+				{
+					TaxYear: "2023-24", Quantity: 1000, Proceeds: 0, AllowableCost: 10000, Gain: -10000,
+					Matches: []*Acquisition{{Rule: PoolRule, Quantity: 1000, Cost: 10000}},
+				},
+				{
+					TaxYear: "2024-25", Quantity: 1000, Proceeds: 800, AllowableCost: 0, Gain: 800,
+					Matches: []*Acquisition{{Rule: PoolRule, Quantity: 1000, Cost: 0}},
+				},
+			},
+		},
+		{
+			// The shares are reacquired at the value of the claim
+			name: "negligible value", desc: "0.5",
+			want: []*Disposal{
+				{
+					TaxYear: "2023-24", Quantity: 1000, Proceeds: 500, AllowableCost: 10000, Gain: -9500,
+					Matches: []*Acquisition{{Rule: PoolRule, Quantity: 1000, Cost: 10000}},
+				},
+				{
+					TaxYear: "2024-25", Quantity: 1000, Proceeds: 800, AllowableCost: 500, Gain: 300,
+					Matches: []*Acquisition{{Rule: PoolRule, Quantity: 1000, Cost: 500}},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			holdings, err := ByTicker([]*record.Record{
+				trade(day(2023, time.January, 10, 10), record.Buy, "ABC", 1000, 10000),
+				corporate(day(2023, time.June, 1, 0), record.NegligibleValue, "ABC", tc.desc),
+				trade(day(2024, time.May, 1, 10), record.Sell, "ABC", 1000, 800),
+			})
+			if err != nil {
+				t.Fatalf("ByTicker() failed: %v", err)
+			}
+			checkDisposals(t, holdings, tc.want)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package holdings
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+)
+
+// incomeStats stores the income from a ticker in a tax year, in GBP
+type incomeStats struct {
+	// dividends are net of the witholding tax, and include the cash alternative of scrip dividends
+	dividends, eri float64
+	// interest is savings income, and accrued is the accrued income scheme adjustment of the bonds
+	// sold (a charge) or bought (a relief)
+	interest, accrued float64
+}
+
+func (s *incomeStats) total() float64 {
+	return s.dividends + s.eri + s.interest + s.accrued
+}
+
+// accruedIncomeThreshold is the nominal value of the bonds in GBP, which has to be exceeded in the tax year or
+// the one before it for the accrued income scheme to apply
+const accruedIncomeThreshold = 5000.0
+
+// accruedIncomeDate returns the date the accrued interest of a bond trade is taxed on, which is the
+// end of the interest period i.e. the next coupon of the bond. The date of the trade is used if there
+// is no coupon after it yet.
+func accruedIncomeDate(r *record.Record, records []*record.Record) time.Time {
+	res := r.Timestamp
+	for _, other := range records {
+		if other.Action != record.Interest || other.Ticker != r.Ticker || other.Timestamp.Before(r.Timestamp) {
+			continue
+		}
+		if res.Equal(r.Timestamp) || other.Timestamp.Before(res) {
+			res = other.Timestamp
+		}
+	}
+	return res
+}
+
+// bondTrades returns the trades of the bonds in the taxable accounts, sorted by time
+func bondTrades(records []*record.Record) []*record.Record {
+	var res []*record.Record
+	for _, r := range records {
+		if r.Broker.CGTExempt || (r.Action != record.Buy && r.Action != record.Sell) {
+			continue
+		}
+		if meta, err := db.TickerMeta(r.Ticker); err == nil && meta.AssetType.IsBond() {
+			res = append(res, r)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Timestamp.Before(res[j].Timestamp)
+	})
+	return res
+}
+
+// accruedIncomeApplies returns whether the nominal value of all the bonds held goes above the threshold
+// of the accrued income scheme at any time in the tax year or the one before it. The nominal value is
+// the quantity, as gilts and qualifying corporate bonds are in GBP.
+func accruedIncomeApplies(trades []*record.Record, year string) (bool, error) {
+	prev, err := offsetTaxYear(year, -1)
+	if err != nil {
+		return false, err
+	}
+	var held float64
+	for _, r := range trades {
+		ty := getTaxYear(r.Timestamp)
+		if ty > year {
+			break
+		}
+		// the nominal value held before the trade is held in its tax year as well
+		if (ty == prev || ty == year) && held > accruedIncomeThreshold {
+			return true, nil
+		}
+		if r.Action == record.Buy {
+			held += r.ShareCount
+		} else {
+			held -= r.ShareCount
+		}
+		if (ty == prev || ty == year) && held > accruedIncomeThreshold {
+			return true, nil
+		}
+	}
+	// the nominal value held after the last trade is held until the end of the tax year
+	return held > accruedIncomeThreshold, nil
+}
+
+// income returns the income keyed by tax year and then by ticker.
+// Income in CGT exempt accounts is skipped, as those are tax free wrappers.
+func income(records []*record.Record) map[string]map[string]*incomeStats {
+	res := make(map[string]map[string]*incomeStats)
+	bonds := bondTrades(records)
+	// whether the accrued income scheme applies, keyed by tax year
+	accrued := make(map[string]bool)
+	for _, r := range records {
+		if r.Broker.CGTExempt {
+			continue
+		}
+		ts := r.Timestamp
+		switch {
+		case r.Action.IsIncome():
+		case (r.Action == record.Buy || r.Action == record.Sell) && r.AccruedInterest != 0.0:
+			ts = accruedIncomeDate(r, records)
+		default:
+			continue
+		}
+		year := getTaxYear(ts)
+		if year == "" {
+			log.Errorf("Cannot calculate tax year of income record %v", r)
+			continue
+		}
+		if !r.Action.IsIncome() {
+			applies, ok := accrued[year]
+			if !ok {
+				var err error
+				if applies, err = accruedIncomeApplies(bonds, year); err != nil {
+					log.Errorf("Cannot check the accrued income scheme of record %v: %v", r, err)
+					continue
+				}
+				accrued[year] = applies
+			}
+			if !applies {
+				continue
+			}
+		}
+		if _, ok := res[year]; !ok {
+			res[year] = make(map[string]*incomeStats)
+		}
+		if _, ok := res[year][r.Ticker]; !ok {
+			res[year][r.Ticker] = &incomeStats{}
+		}
+		switch r.Action {
+		case record.Dividend, record.ScripDividend:
+			res[year][r.Ticker].dividends += r.Total
+		case record.ExcessReportableIncome:
+			res[year][r.Ticker].eri += r.Total
+		case record.Interest:
+			res[year][r.Ticker].interest += r.Total
+		case record.Sell:
+			res[year][r.Ticker].accrued += r.AccruedInterest
+		case record.Buy:
+			res[year][r.Ticker].accrued -= r.AccruedInterest
+		}
+	}
+	return res
+}
+
+// IncomeTables returns a table with the dividends and excess reportable income of every ticker
+// of the owner for each tax year
+func IncomeTables(records []*record.Record, owner string) map[string]table.Writer {
+	tables := make(map[string]table.Writer)
+	for ty, byTicker := range income(recordsOfOwner(records, owner)) {
+		t := table.NewWriter()
+		t.SetTitle(fmt.Sprintf("Income in tax year %s", ty))
+		t.SetStyle(table.StyleLight)
+		t.AppendHeader(table.Row{
+			"Ticker", "Dividends (GBP)", "Excess Reportable Income (GBP)", "Interest (GBP)",
+			"Accrued Income (GBP)", "Total (GBP)",
+		})
+		t.SetColumnConfigs([]table.ColumnConfig{
+			{Number: 2, Transformer: tf, TransformerFooter: tf},
+			{Number: 3, Transformer: tf, TransformerFooter: tf},
+			{Number: 4, Transformer: tf, TransformerFooter: tf},
+			{Number: 5, Transformer: tf, TransformerFooter: tf},
+			{Number: 6, Transformer: tf, TransformerFooter: tf},
+		})
+		t.SortBy([]table.SortBy{
+			{Number: 1},
+		})
+		var total incomeStats
+		for ticker, st := range byTicker {
+			t.AppendRow(table.Row{
+				ticker, st.dividends, st.eri, st.interest, st.accrued, st.total(),
+			})
+			total.dividends += st.dividends
+			total.eri += st.eri
+			total.interest += st.interest
+			total.accrued += st.accrued
+		}
+		t.AppendFooter(table.Row{
+			"TOTAL", total.dividends, total.eri, total.interest, total.accrued, total.total(),
+		})
+		tables[ty] = t
+	}
+	return tables
+}
@@ -0,0 +1,74 @@
+package holdings
+
+import (
+	"testing"
+	"time"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+)
+
+// giltTrade returns a BUY or SELL of the nominal of a gilt at par, with the accrued interest since the last coupon
+func giltTrade(ts time.Time, action record.TransactionType, nominal, accrued float64) *record.Record {
+	r := trade(ts, action, "TG25", nominal, nominal)
+	r.AccruedInterest = accrued
+	return r
+}
+
+func TestAccruedIncomeThreshold(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	db.SetAssetType("TG25", record.GILT_ASSET)
+	for _, tc := range []struct {
+		name    string
+		records []*record.Record
+		// want is the accrued income of 2023-24
+		want float64
+	}{
+		{
+			name: "below the threshold",
+			records: []*record.Record{
+				giltTrade(day(2023, time.June, 1, 10), record.Buy, 3000, 10),
+				giltTrade(day(2023, time.September, 1, 10), record.Sell, 3000, 30),
+			},
+		},
+		{
+			name: "above the threshold",
+			records: []*record.Record{
+				giltTrade(day(2023, time.June, 1, 10), record.Buy, 10000, 10),
+				giltTrade(day(2023, time.September, 1, 10), record.Sell, 10000, 30),
+			},
+			want: 20,
+		},
+		{
+			// The nominal value held in the tax year before counts as well
+			name: "above the threshold in the tax year before",
+			records: []*record.Record{
+				giltTrade(day(2022, time.June, 1, 10), record.Buy, 6000, 0),
+				giltTrade(day(2022, time.December, 1, 10), record.Sell, 6000, 0),
+				giltTrade(day(2023, time.June, 1, 10), record.Buy, 1000, 10),
+				giltTrade(day(2023, time.September, 1, 10), record.Sell, 1000, 30),
+			},
+			want: 20,
+		},
+		{
+			// The nominal value held from before is above the threshold without any trade in the tax year before
+			name: "held from before",
+			records: []*record.Record{
+				giltTrade(day(2020, time.June, 1, 10), record.Buy, 6000, 0),
+				giltTrade(day(2023, time.June, 1, 10), record.Buy, 1000, 10),
+				giltTrade(day(2023, time.September, 1, 10), record.Sell, 1000, 30),
+			},
+			want: 20,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got float64
+			if st, ok := income(tc.records)["2023-24"]["TG25"]; ok {
+				got = st.accrued
+			}
+			if !near(got, tc.want) {
+				t.Errorf("got accrued income %.2f in 2023-24, want %.2f", got, tc.want)
+			}
+		})
+	}
+}
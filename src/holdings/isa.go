@@ -0,0 +1,129 @@
+package holdings
+
+import (
+	"fmt"
+	"sort"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	log "github.com/sirupsen/logrus"
+)
+
+// isaAllowances is the annual limit of the subscriptions to all the ISAs of a person for each tax year.
+// Add an entry here whenever a new tax year starts.
+var isaAllowances = map[string]float64{
+	"2011-12": 10680,
+	"2012-13": 11280,
+	"2013-14": 11520,
+	"2014-15": 15000,
+	"2015-16": 15240,
+	"2016-17": 15240,
+	"2017-18": 20000,
+	"2018-19": 20000,
+	"2019-20": 20000,
+	"2020-21": 20000,
+	"2021-22": 20000,
+	"2022-23": 20000,
+	"2023-24": 20000,
+	"2024-25": 20000,
+	"2025-26": 20000,
+	"2026-27": 20000,
+}
+
+// lisaAllowance is the annual limit of the subscriptions to a LISA, which also count towards the ISA allowance
+const lisaAllowance = 4000.0
+
+// isaSubscriptions returns the cash paid into every ISA of the owner in GBP, keyed by tax year and then by account
+func isaSubscriptions(records []*record.Record, owner string) map[string]map[record.Account]float64 {
+	res := make(map[string]map[record.Account]float64)
+	for _, r := range recordsOfOwner(records, owner) {
+		if r.Action != record.CashIn || !r.Broker.Type.IsISA() {
+			continue
+		}
+		year := getTaxYear(r.Timestamp)
+		if year == "" {
+			log.Errorf("Cannot calculate tax year of ISA subscription %v", r)
+			continue
+		}
+		amount := r.ShareCount
+		if r.Currency != record.GBP {
+			rate, err := db.GetForex(r.Timestamp, r.Currency)
+			if err != nil {
+				log.Errorf("Cannot get forex of ISA subscription %v: %v", r, err)
+				continue
+			}
+			amount *= rate
+		}
+		if _, ok := res[year]; !ok {
+			res[year] = make(map[record.Account]float64)
+		}
+		res[year][r.Broker] += amount
+	}
+	return res
+}
+
+// allowanceStatus returns whether the subscriptions are within the allowance
+func allowanceStatus(subscribed, allowance float64) string {
+	if subscribed > allowance+epsilon {
+		return fmt.Sprintf("BREACHED by %.2f", subscribed-allowance)
+	}
+	return "OK"
+}
+
+// ISATables returns a table with the subscriptions of the owner to every ISA for each tax year,
+// flagging the breaches of the annual ISA allowance and of the LISA allowance
+func ISATables(records []*record.Record, owner string) map[string]table.Writer {
+	tables := make(map[string]table.Writer)
+	for ty, byAccount := range isaSubscriptions(records, owner) {
+		t := table.NewWriter()
+		t.SetTitle(fmt.Sprintf("ISA subscriptions in tax year %s", ty))
+		t.SetStyle(table.StyleLight)
+		t.AppendHeader(table.Row{
+			"Account", "Type", "Subscribed (GBP)", "Allowance (GBP)", "Remaining (GBP)", "Status",
+		})
+		t.SetColumnConfigs([]table.ColumnConfig{
+			{Number: 3, Transformer: tf, TransformerFooter: tf},
+			{Number: 4, Align: text.AlignRight, AlignFooter: text.AlignRight},
+			{Number: 5, Align: text.AlignRight, AlignFooter: text.AlignRight},
+		})
+		accounts := make([]record.Account, 0, len(byAccount))
+		for act := range byAccount {
+			accounts = append(accounts, act)
+		}
+		sort.Slice(accounts, func(i, j int) bool {
+			return accounts[i].Name < accounts[j].Name
+		})
+		var total, lisaTotal float64
+		var hasLISA bool
+		for _, act := range accounts {
+			subscribed := byAccount[act]
+			total += subscribed
+			if act.Type == record.LISA_ACCOUNT {
+				lisaTotal += subscribed
+				hasLISA = true
+			}
+			t.AppendRow(table.Row{act.Name, act.Type, subscribed, "", "", ""})
+		}
+		// the LISAs have their own allowance as well, across all of them like the ISA allowance
+		if hasLISA {
+			t.AppendSeparator()
+			t.AppendRow(table.Row{
+				"LISA TOTAL", record.LISA_ACCOUNT, lisaTotal, tf(lisaAllowance), tf(lisaAllowance - lisaTotal),
+				allowanceStatus(lisaTotal, lisaAllowance),
+			})
+		}
+		allowance, ok := isaAllowances[ty]
+		if !ok {
+			log.Errorf("No ISA allowance known for tax year %s", ty)
+			t.AppendFooter(table.Row{"TOTAL", "", total, "", "", "UNKNOWN"})
+		} else {
+			t.AppendFooter(table.Row{
+				"TOTAL", "", total, tf(allowance), tf(allowance - total), allowanceStatus(total, allowance),
+			})
+		}
+		tables[ty] = t
+	}
+	return tables
+}
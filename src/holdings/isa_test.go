@@ -0,0 +1,66 @@
+package holdings
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+)
+
+// subscription returns the record of paying amount in GBP into the ISA account
+func subscription(name string, accountType record.AccountType, amount float64) *record.Record {
+	act := record.Account{Name: name, Currency: record.GBP, CGTExempt: true, Type: accountType}
+	return &record.Record{
+		Timestamp:  day(2023, time.July, 1, 10),
+		Broker:     act,
+		Action:     record.CashIn,
+		Ticker:     "GBP",
+		ShareCount: amount,
+		Currency:   record.GBP,
+	}
+}
+
+func TestISALimits(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	for _, tc := range []struct {
+		name    string
+		records []*record.Record
+		// want is in the rendered table of 2023-24, wantNot must not be, ignoring the case as the footer
+		// is in upper case
+		want, wantNot string
+	}{
+		{
+			name:    "within the allowances",
+			records: []*record.Record{subscription("ISA", record.ISA_ACCOUNT, 16000), subscription("LISA", record.LISA_ACCOUNT, 4000)},
+			wantNot: "BREACHED",
+		},
+		{
+			name:    "ISA allowance across accounts",
+			records: []*record.Record{subscription("ISA1", record.ISA_ACCOUNT, 15000), subscription("ISA2", record.ISA_ACCOUNT, 6000)},
+			want:    "BREACHED BY 1000.00",
+		},
+		{
+			// Neither LISA is above the limit on its own
+			name:    "LISA allowance across accounts",
+			records: []*record.Record{subscription("LISA1", record.LISA_ACCOUNT, 2500), subscription("LISA2", record.LISA_ACCOUNT, 2500)},
+			want:    "BREACHED BY 1000.00",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tables := ISATables(tc.records, "")
+			got, ok := tables["2023-24"]
+			if !ok {
+				t.Fatalf("got tables for %v, want 2023-24", tables)
+			}
+			s := strings.ToUpper(got.Render())
+			if tc.want != "" && !strings.Contains(s, tc.want) {
+				t.Errorf("got table\n%s\nwant %q", s, tc.want)
+			}
+			if tc.wantNot != "" && strings.Contains(s, tc.wantNot) {
+				t.Errorf("got table\n%s\nwant no %q", s, tc.wantNot)
+			}
+		})
+	}
+}
@@ -0,0 +1,391 @@
+package holdings
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Liability stores the CGT due for a tax year along with how it was calculated
+type Liability struct {
+	TaxYear string
+	// Gain is the net gain of the tax year, i.e. gains minus the losses of the same year
+	Gain float64
+	// Losses from previous years available at the start of the tax year, how much of them
+	// was set off against the gain and what is left for the following years
+	LossesBroughtForward, LossesUsed, LossesCarriedForward float64
+
+	AnnualExemptAmount float64
+	TaxableGain        float64
+	TaxableIncome      float64
+	// Parts of the taxable gain which fall in the basic and higher rate bands
+	BasicRateGain, HigherRateGain float64
+	// BasicRate and HigherRate are the rates of the last rate period
+	BasicRate, HigherRate float64
+	Tax                   float64
+	// Periods splits the liability by the rate periods of the tax year
+	Periods []*PeriodLiability
+}
+
+// PeriodLiability stores the CGT due on the disposals of a rate period within a tax year
+type PeriodLiability struct {
+	// From is the first day of the period, zero for the start of the tax year
+	From time.Time
+	// Gains are the gains of the disposals in the period, before any losses
+	Gains float64
+	// Deductions are the losses and the annual exempt amount set off against the gains of the period
+	Deductions                    float64
+	TaxableGain                   float64
+	BasicRateGain, HigherRateGain float64
+	BasicRate, HigherRate         float64
+	Tax                           float64
+}
+
+// Rates returns the basic and higher rates of every rate period
+func (l *Liability) Rates() string {
+	var res []string
+	for _, p := range l.Periods {
+		res = append(res, fmt.Sprintf("%.0f%% / %.0f%%", p.BasicRate*100.0, p.HigherRate*100.0))
+	}
+	return strings.Join(res, ", ")
+}
+
+// yearTotals returns the stats across all the taxable pools for every tax year
+func yearTotals(holdings map[string]*Holding) map[string]*stats {
+	res := make(map[string]*stats)
+	for _, h := range holdings {
+		for ty, st := range h.taxable.yearStats {
+			if _, ok := res[ty]; !ok {
+				res[ty] = &stats{}
+			}
+			res[ty].add(st)
+		}
+	}
+	return res
+}
+
+// Loss stores the net loss of a tax year and how it was used in the following years
+type Loss struct {
+	TaxYear string
+	Amount  float64
+	// Claimed is set if the loss has been reported to HMRC
+	Claimed bool
+	// ClaimDeadline is the last day the loss can be claimed, four years after the end of the tax year
+	ClaimDeadline time.Time
+	Used          float64
+	// UsedIn stores the tax years the loss was set off against
+	UsedIn []string
+}
+
+// Remaining returns the part of the loss not used yet
+func (l *Loss) Remaining() float64 {
+	return l.Amount - l.Used
+}
+
+// Expired returns whether the loss can no longer be used at the given time,
+// which only happens for losses never claimed.
+func (l *Loss) Expired(ts time.Time) bool {
+	return !l.Claimed && ts.After(l.ClaimDeadline)
+}
+
+func newLoss(year string, amount float64, cfg *TaxYearConfig) *Loss {
+	l := &Loss{
+		TaxYear: year,
+		Amount:  amount,
+	}
+	if ty, ok := taxYears[year]; ok {
+		l.ClaimDeadline = ty.end.AddDate(4, 0, 0)
+	}
+	if cfg != nil {
+		l.Claimed = cfg.LossesClaimed
+	}
+	return l
+}
+
+// useLosses sets off the losses brought forward, oldest first, against the gain above the
+// annual exempt amount of the given tax year. It returns the losses available and the losses used.
+func useLosses(losses []*Loss, year string, gainAboveExempt float64) (float64, float64) {
+	var available, used float64
+	var end time.Time
+	if ty, ok := taxYears[year]; ok {
+		end = ty.end
+	}
+	for _, l := range losses {
+		if l.Remaining() <= epsilon || l.Expired(end) {
+			continue
+		}
+		available += l.Remaining()
+		use := math.Min(l.Remaining(), gainAboveExempt-used)
+		if use <= epsilon {
+			continue
+		}
+		l.Used += use
+		l.UsedIn = append(l.UsedIn, year)
+		used += use
+	}
+	return available, used
+}
+
+func newLiability(year string, gain, lossesUsed float64, periodGains []float64, rules *taxRules, cfg *TaxYearConfig) *Liability {
+	l := &Liability{
+		TaxYear:            year,
+		Gain:               gain,
+		LossesUsed:         lossesUsed,
+		AnnualExemptAmount: rules.annualExemptAmount,
+	}
+	if cfg != nil {
+		l.TaxableIncome = cfg.TaxableIncome
+	}
+	l.TaxableGain = math.Max(0.0, gain-lossesUsed-rules.annualExemptAmount)
+	// The gains use up whatever is left of the basic rate band after the income
+	bandLeft := math.Max(0.0, rules.basicRateBand-l.TaxableIncome)
+	var total float64
+	for _, g := range periodGains {
+		total += g
+	}
+	l.Periods = allocatePeriods(rules.periods(), periodGains, total-l.TaxableGain, bandLeft)
+	for _, p := range l.Periods {
+		l.BasicRateGain += p.BasicRateGain
+		l.HigherRateGain += p.HigherRateGain
+		l.BasicRate, l.HigherRate = p.BasicRate, p.HigherRate
+		l.Tax += p.Tax
+	}
+	return l
+}
+
+// allocatePeriods sets off the deductions, i.e. the losses and the annual exempt amount, against the gains
+// of the rate periods and uses the basic rate band left for them, in the order which gives the least tax.
+func allocatePeriods(periods []*ratePeriod, gains []float64, deductions, bandLeft float64) []*PeriodLiability {
+	var best []*PeriodLiability
+	var bestTax float64
+	for _, order := range permutations(len(periods)) {
+		res := make([]*PeriodLiability, len(periods))
+		for i, p := range periods {
+			res[i] = &PeriodLiability{From: p.from, Gains: gains[i], BasicRate: p.basicRate, HigherRate: p.higherRate}
+		}
+		left := deductions
+		for _, i := range order {
+			res[i].Deductions = math.Min(left, res[i].Gains)
+			res[i].TaxableGain = res[i].Gains - res[i].Deductions
+			left -= res[i].Deductions
+		}
+		// The band saves the most where the higher rate is furthest from the basic rate
+		byBand := make([]*PeriodLiability, len(res))
+		copy(byBand, res)
+		sort.SliceStable(byBand, func(i, j int) bool {
+			return byBand[i].HigherRate-byBand[i].BasicRate > byBand[j].HigherRate-byBand[j].BasicRate
+		})
+		band := bandLeft
+		var tax float64
+		for _, p := range byBand {
+			p.BasicRateGain = math.Min(p.TaxableGain, band)
+			p.HigherRateGain = p.TaxableGain - p.BasicRateGain
+			band -= p.BasicRateGain
+			p.Tax = p.BasicRateGain*p.BasicRate + p.HigherRateGain*p.HigherRate
+			tax += p.Tax
+		}
+		if best == nil || tax < bestTax-epsilon {
+			best, bestTax = res, tax
+		}
+	}
+	return best
+}
+
+// permutations returns all the orderings of 0, 1, ..., n-1
+func permutations(n int) [][]int {
+	if n == 0 {
+		return [][]int{{}}
+	}
+	var res [][]int
+	for _, p := range permutations(n - 1) {
+		for i := 0; i <= len(p); i++ {
+			q := append(append(append([]int{}, p[:i]...), n-1), p[i:]...)
+			res = append(res, q)
+		}
+	}
+	return res
+}
+
+// periodGains returns the gains before losses of the disposals in every rate period of the tax years
+func periodGains(holdings map[string]*Holding) map[string][]float64 {
+	res := make(map[string][]float64)
+	for _, d := range Disposals(holdings) {
+		if d.Gain <= 0.0 {
+			continue
+		}
+		rules, err := rulesForTaxYear(d.TaxYear)
+		if err != nil {
+			continue
+		}
+		if _, ok := res[d.TaxYear]; !ok {
+			res[d.TaxYear] = make([]float64, len(rules.periods()))
+		}
+		res[d.TaxYear][rules.periodOf(d.Record.Timestamp)] += d.Gain
+	}
+	return res
+}
+
+// Liabilities returns the CGT liability for every tax year with a disposal along with the ledger
+// of net losses, both sorted by tax year.
+// configs stores the personal details for a tax year, keyed by the name of the tax year.
+// Losses of previous years are only used to bring the gain of a tax year down to the annual exempt amount,
+// the rest being carried forward.
+func Liabilities(holdings map[string]*Holding, configs map[string]*TaxYearConfig) ([]*Liability, []*Loss) {
+	totals := yearTotals(holdings)
+	byPeriod := periodGains(holdings)
+	years := maps.Keys(totals)
+	slices.Sort(years)
+	var res []*Liability
+	var losses []*Loss
+	for _, ty := range years {
+		cfg, ok := configs[ty]
+		// Losses of the year are set off against the gains of the same year first
+		net := totals[ty].gains - totals[ty].losses
+		rules, err := rulesForTaxYear(ty)
+		if err != nil {
+			log.Errorf("Cannot calculate CGT liability for tax year %s: %v", ty, err)
+		} else {
+			if !ok {
+				log.Warningf("No taxable income configured for tax year %s, assuming the whole basic rate band is available", ty)
+			}
+			gain := math.Max(0.0, net)
+			available, used := useLosses(losses, ty, gain-rules.annualExemptAmount)
+			gains := byPeriod[ty]
+			if gains == nil {
+				gains = make([]float64, len(rules.periods()))
+			}
+			l := newLiability(ty, gain, used, gains, rules, cfg)
+			l.LossesBroughtForward = available
+			// The net loss of the year, if any, is carried forward as well
+			l.LossesCarriedForward = available - used + math.Max(0.0, -net)
+			res = append(res, l)
+		}
+		if net < -epsilon {
+			losses = append(losses, newLoss(ty, -net, cfg))
+		}
+	}
+	return res, losses
+}
+
+// LiabilityTable returns a table with the CGT liability for every tax year
+func LiabilityTable(holdings map[string]*Holding, configs map[string]*TaxYearConfig) table.Writer {
+	t := table.NewWriter()
+	t.SetTitle("CGT Liability")
+	t.SetStyle(table.StyleLight)
+	t.AppendHeader(table.Row{
+		"Tax Year", "Gain (GBP)", "Losses Brought Forward", "Losses Used", "Losses Carried Forward",
+		"Annual Exempt Amount", "Taxable Gain",
+		"Taxable Income", "Basic Rate Gain", "Higher Rate Gain", "Rates", "CGT Due (GBP)",
+	})
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 2, Transformer: tf},
+		{Number: 3, Transformer: tf},
+		{Number: 4, Transformer: tf},
+		{Number: 5, Transformer: tf},
+		{Number: 6, Transformer: tf},
+		{Number: 7, Transformer: tf},
+		{Number: 8, Transformer: tf},
+		{Number: 9, Transformer: tf},
+		{Number: 10, Transformer: tf},
+		{Number: 12, Transformer: tf},
+	})
+	liabilities, _ := Liabilities(holdings, configs)
+	for _, l := range liabilities {
+		t.AppendRow(table.Row{
+			l.TaxYear, l.Gain, l.LossesBroughtForward, l.LossesUsed, l.LossesCarriedForward,
+			l.AnnualExemptAmount, l.TaxableGain,
+			l.TaxableIncome, l.BasicRateGain, l.HigherRateGain,
+			l.Rates(),
+			l.Tax,
+		})
+	}
+	return t
+}
+
+// RatePeriodTable returns a table splitting the CGT liability of the tax years where the rates changed
+// part-way through, by the rate periods
+func RatePeriodTable(holdings map[string]*Holding, configs map[string]*TaxYearConfig) table.Writer {
+	t := table.NewWriter()
+	t.SetTitle("CGT Rate Periods")
+	t.SetStyle(table.StyleLight)
+	t.AppendHeader(table.Row{
+		"Tax Year", "From", "Gains (GBP)", "Losses and Exempt Amount", "Taxable Gain",
+		"Basic Rate Gain", "Higher Rate Gain", "Rates", "CGT Due (GBP)",
+	})
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 1, AutoMerge: true},
+		{Number: 3, Transformer: tf},
+		{Number: 4, Transformer: tf},
+		{Number: 5, Transformer: tf},
+		{Number: 6, Transformer: tf},
+		{Number: 7, Transformer: tf},
+		{Number: 9, Transformer: tf},
+	})
+	liabilities, _ := Liabilities(holdings, configs)
+	for _, l := range liabilities {
+		if len(l.Periods) <= 1 {
+			continue
+		}
+		for _, p := range l.Periods {
+			from := "Start of tax year"
+			if !p.From.IsZero() {
+				from = p.From.Format("2006-01-02")
+			}
+			t.AppendRow(table.Row{
+				l.TaxYear, from, p.Gains, p.Deductions, p.TaxableGain,
+				p.BasicRateGain, p.HigherRateGain,
+				fmt.Sprintf("%.0f%% / %.0f%%", p.BasicRate*100.0, p.HigherRate*100.0), p.Tax,
+			})
+		}
+	}
+	return t
+}
+
+// LossTable returns a table with the net loss of every tax year and how it was carried forward
+func LossTable(holdings map[string]*Holding, configs map[string]*TaxYearConfig) table.Writer {
+	t := table.NewWriter()
+	t.SetTitle("Capital Losses")
+	t.SetStyle(table.StyleLight)
+	t.AppendHeader(table.Row{
+		"Tax Year", "Loss (GBP)", "Claimed", "Claim Deadline", "Used", "Used In", "Remaining", "Status",
+	})
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 2, Transformer: tf, TransformerFooter: tf},
+		{Number: 5, Transformer: tf, TransformerFooter: tf},
+		{Number: 7, Transformer: tf, TransformerFooter: tf},
+	})
+	_, losses := Liabilities(holdings, configs)
+	now := time.Now()
+	var total, used, remaining float64
+	for _, l := range losses {
+		status := "Available"
+		switch {
+		case l.Remaining() <= epsilon:
+			status = "Used"
+		case l.Expired(now):
+			status = "Expired"
+		case !l.Claimed:
+			status = "Claim needed"
+		}
+		t.AppendRow(table.Row{
+			l.TaxYear, l.Amount, l.Claimed, l.ClaimDeadline.Format("2006-01-02"),
+			l.Used, strings.Join(l.UsedIn, ", "), l.Remaining(), status,
+		})
+		total += l.Amount
+		used += l.Used
+		if !l.Expired(now) {
+			remaining += l.Remaining()
+		}
+	}
+	t.AppendFooter(table.Row{
+		"TOTAL", total, "", "", used, "", remaining, "",
+	})
+	return t
+}
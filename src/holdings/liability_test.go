@@ -0,0 +1,161 @@
+package holdings
+
+import (
+	"testing"
+	"time"
+
+	"aagr.xyz/trades/record"
+)
+
+// realise returns the records of buying 1000 shares of the ticker for 10000 a month before sold,
+// and selling them on sold at the given gain, which is negative for a loss
+func realise(ticker string, sold time.Time, gain float64) []*record.Record {
+	return []*record.Record{
+		trade(sold.AddDate(0, -1, 0), record.Buy, ticker, 1000, 10000),
+		trade(sold, record.Sell, ticker, 1000, 10000+gain),
+	}
+}
+
+func TestSplitRateAllocation(t *testing.T) {
+	// A gain of 20000 before and after the rates changed on 30 October 2024
+	var records []*record.Record
+	records = append(records, realise("ABC", day(2024, time.June, 3, 10), 20000)...)
+	records = append(records, realise("XYZ", day(2024, time.December, 2, 10), 20000)...)
+	holdings, err := ByTicker(records)
+	if err != nil {
+		t.Fatalf("ByTicker() failed: %v", err)
+	}
+	for _, tc := range []struct {
+		name   string
+		income float64
+		// want are the deductions and the basic rate gain of each period, and the total tax
+		wantDeductions, wantBasic []float64
+		wantTax                   float64
+	}{
+		{
+			// The annual exempt amount goes against the gain taxed at 24% instead of 20%
+			name: "higher rate", income: 50000,
+			wantDeductions: []float64{0, 3000}, wantBasic: []float64{0, 0},
+			wantTax: 20000*0.20 + 17000*0.24,
+		},
+		{
+			// The annual exempt amount goes against the gain taxed at 18% instead of 10%
+			name: "basic rate", income: 0,
+			wantDeductions: []float64{0, 3000}, wantBasic: []float64{20000, 17000},
+			wantTax: 20000*0.10 + 17000*0.18,
+		},
+		{
+			// The 17700 of the basic rate band left saves 10% before the change but only 6% after it
+			name: "across the bands", income: 20000,
+			wantDeductions: []float64{0, 3000}, wantBasic: []float64{17700, 0},
+			wantTax: 17700*0.10 + 2300*0.20 + 17000*0.24,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			liabilities, _ := Liabilities(holdings, map[string]*TaxYearConfig{"2024-25": {TaxableIncome: tc.income}})
+			if len(liabilities) != 1 || liabilities[0].TaxYear != "2024-25" {
+				t.Fatalf("got %d liabilities, want 1 for 2024-25", len(liabilities))
+			}
+			l := liabilities[0]
+			if !near(l.TaxableGain, 37000) || !near(l.Tax, tc.wantTax) {
+				t.Errorf("got taxable gain %.2f and tax %.2f, want 37000 and %.2f", l.TaxableGain, l.Tax, tc.wantTax)
+			}
+			if len(l.Periods) != 2 {
+				t.Fatalf("got %d rate periods, want 2", len(l.Periods))
+			}
+			for i, p := range l.Periods {
+				if !near(p.Gains, 20000) || !near(p.Deductions, tc.wantDeductions[i]) || !near(p.BasicRateGain, tc.wantBasic[i]) {
+					t.Errorf("period %d: got gains %.2f, deductions %.2f and basic rate gain %.2f, want 20000, %.2f and %.2f",
+						i, p.Gains, p.Deductions, p.BasicRateGain, tc.wantDeductions[i], tc.wantBasic[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLossCarryForward(t *testing.T) {
+	type year struct {
+		taxYear                                       string
+		broughtForward, used, carriedForward, taxable float64
+	}
+	for _, tc := range []struct {
+		name string
+		// gains realised on the days, negative for a loss
+		gains map[time.Time]float64
+		want  []year
+	}{
+		{
+			// The losses brought forward only bring the gain down to the annual exempt amount
+			name: "above the exempt amount",
+			gains: map[time.Time]float64{
+				day(2022, time.June, 1, 10): -10000,
+				day(2023, time.June, 1, 10): 8000,
+				day(2024, time.June, 3, 10): 4000,
+			},
+			want: []year{
+				{taxYear: "2022-23", carriedForward: 10000},
+				{taxYear: "2023-24", broughtForward: 10000, used: 2000, carriedForward: 8000},
+				{taxYear: "2024-25", broughtForward: 8000, used: 1000, carriedForward: 7000},
+			},
+		},
+		{
+			name: "below the exempt amount",
+			gains: map[time.Time]float64{
+				day(2022, time.June, 1, 10): -10000,
+				day(2023, time.June, 1, 10): 5000,
+			},
+			want: []year{
+				{taxYear: "2022-23", carriedForward: 10000},
+				{taxYear: "2023-24", broughtForward: 10000, carriedForward: 10000},
+			},
+		},
+		{
+			// The losses of the year are set off in full before the losses brought forward
+			name: "losses of the year first",
+			gains: map[time.Time]float64{
+				day(2022, time.June, 1, 10): -10000,
+				day(2023, time.June, 1, 10): 15000,
+				day(2023, time.July, 3, 10): -5000,
+			},
+			want: []year{
+				{taxYear: "2022-23", carriedForward: 10000},
+				{taxYear: "2023-24", broughtForward: 10000, used: 4000, carriedForward: 6000},
+			},
+		},
+		{
+			name: "no losses brought forward",
+			gains: map[time.Time]float64{
+				day(2023, time.June, 1, 10): 15000,
+				day(2023, time.July, 3, 10): -5000,
+			},
+			want: []year{
+				{taxYear: "2023-24", taxable: 4000},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var records []*record.Record
+			for sold, gain := range tc.gains {
+				records = append(records, realise(sold.Format("T20060102"), sold, gain)...)
+			}
+			holdings, err := ByTicker(records)
+			if err != nil {
+				t.Fatalf("ByTicker() failed: %v", err)
+			}
+			// The taxable income does not matter, but leaves out the warning
+			configs := map[string]*TaxYearConfig{"2022-23": {}, "2023-24": {}, "2024-25": {}}
+			liabilities, _ := Liabilities(holdings, configs)
+			if len(liabilities) != len(tc.want) {
+				t.Fatalf("got %d liabilities, want %d", len(liabilities), len(tc.want))
+			}
+			for i, w := range tc.want {
+				l := liabilities[i]
+				if l.TaxYear != w.taxYear || !near(l.LossesBroughtForward, w.broughtForward) || !near(l.LossesUsed, w.used) ||
+					!near(l.LossesCarriedForward, w.carriedForward) || !near(l.TaxableGain, w.taxable) {
+					t.Errorf("got %s with losses brought forward %.2f, used %.2f, carried forward %.2f and taxable gain %.2f, want %+v",
+						l.TaxYear, l.LossesBroughtForward, l.LossesUsed, l.LossesCarriedForward, l.TaxableGain, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package holdings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"aagr.xyz/trades/record"
+	"golang.org/x/exp/maps"
+)
+
+// Owners returns the owners of the accounts in the records, sorted
+func Owners(records []*record.Record) []string {
+	owners := make(map[string]bool)
+	for _, r := range records {
+		if r.Broker != record.GlobalBroker {
+			owners[r.Broker.Owner] = true
+		}
+	}
+	res := maps.Keys(owners)
+	sort.Strings(res)
+	return res
+}
+
+// recordsOfOwner returns the records of the accounts of the owner, along with the global records
+func recordsOfOwner(records []*record.Record, owner string) []*record.Record {
+	var res []*record.Record
+	for _, r := range records {
+		if r.Broker == record.GlobalBroker || r.Broker.Owner == owner {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// SpouseTransfers returns a SPOUSETRANSFERIN record for every SPOUSETRANSFEROUT record, which adds the shares
+// to the account of the spouse at the cost in the pool of the owner on the day of the transfer.
+// The transfers are resolved in the order of time, as the shares may be transferred back later on.
+func SpouseTransfers(records []*record.Record) ([]*record.Record, error) {
+	accounts := make(map[string]record.Account)
+	var outs []*record.Record
+	for _, r := range records {
+		if r.Broker != record.GlobalBroker {
+			accounts[strings.ToUpper(r.Broker.Name)] = r.Broker
+		}
+		if r.Action == record.SpouseTransferOut {
+			outs = append(outs, r)
+		}
+	}
+	sort.SliceStable(outs, func(i, j int) bool {
+		return outs[i].Timestamp.Before(outs[j].Timestamp)
+	})
+	var res []*record.Record
+	for _, out := range outs {
+		to, ok := accounts[strings.ToUpper(out.Description)]
+		if !ok {
+			return nil, fmt.Errorf("account %q of spouse not found for %v", out.Description, out)
+		}
+		if to.Owner == out.Broker.Owner {
+			return nil, fmt.Errorf("account %q has the same owner, use a transfer instead of %v", out.Description, out)
+		}
+		// The cost in the pool on the day of the transfer does not depend on anything after it, except the
+		// acquisitions in the next 30 days matched against the earlier disposals.
+		day := out.Timestamp.Truncate(24 * time.Hour)
+		var until []*record.Record
+		for _, r := range recordsOfOwner(append(append([]*record.Record{}, records...), res...), out.Broker.Owner) {
+			if !r.Timestamp.Truncate(24 * time.Hour).After(day.AddDate(0, 0, 30)) {
+				until = append(until, r)
+			}
+		}
+		byTicker, err := ByTicker(until)
+		if err != nil {
+			return nil, fmt.Errorf("cannot calculate the holdings of %q: %v", out.Broker.Owner, err)
+		}
+		h, ok := byTicker[out.Ticker]
+		if !ok {
+			return nil, fmt.Errorf("no holding of %s to transfer for %v", out.Ticker, out)
+		}
+		var in *record.Record
+		for _, t := range h.transfers {
+			if t.Timestamp.Equal(day) && strings.EqualFold(t.Broker.Name, out.Description) &&
+				t.Description == out.Broker.Name && t.ShareCount == out.ShareCount {
+				in = t
+			}
+		}
+		if in == nil {
+			return nil, fmt.Errorf("cannot find the shares transferred by %v", out)
+		}
+		in.Timestamp = out.Timestamp
+		in.Broker = to
+		res = append(res, in)
+	}
+	return res, nil
+}
+
+// ByOwner returns the holdings by ticker of every owner, with separate section 104 pools for each of them.
+// It also returns the SPOUSETRANSFERIN records resolved from the transfers between the owners, to be passed
+// on to ByAccount.
+func ByOwner(records []*record.Record) (map[string]map[string]*Holding, []*record.Record, error) {
+	transfers, err := SpouseTransfers(records)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot resolve spouse transfers: %v", err)
+	}
+	records = append(append([]*record.Record{}, records...), transfers...)
+	res := make(map[string]map[string]*Holding)
+	for _, owner := range Owners(records) {
+		byTicker, err := ByTicker(recordsOfOwner(records, owner))
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot calculate the holdings of %q: %v", owner, err)
+		}
+		res[owner] = byTicker
+	}
+	return res, transfers, nil
+}
+
+// Merge returns the holdings of all the owners together, with the pools of the same ticker added up.
+// The merged holdings are only meant for the portfolio of the household, as CGT is calculated per owner.
+func Merge(byOwner map[string]map[string]*Holding) map[string]*Holding {
+	res := make(map[string]*Holding)
+	owners := maps.Keys(byOwner)
+	sort.Strings(owners)
+	for _, owner := range owners {
+		for ticker, h := range byOwner[owner] {
+			m, ok := res[ticker]
+			if !ok {
+				m = &Holding{
+					ticker:    ticker,
+					currency:  h.currency,
+					taxable:   newPool(),
+					cgtExempt: newPool(),
+				}
+				res[ticker] = m
+			}
+			m.taxable.merge(h.taxable)
+			m.cgtExempt.merge(h.cgtExempt)
+			m.records = append(m.records, h.records...)
+		}
+	}
+	return res
+}
+
+// merge adds the positions, stats and disposals of another pool of the same ticker
+func (p *pool) merge(other *pool) {
+	p.base.buy(other.base.quantity, other.base.totalCost)
+	p.gbp.buy(other.gbp.quantity, other.gbp.totalCost)
+	for ty, st := range other.yearStats {
+		if _, ok := p.yearStats[ty]; !ok {
+			p.yearStats[ty] = &stats{}
+		}
+		p.yearStats[ty].add(st)
+	}
+	p.disposals = append(p.disposals, other.disposals...)
+}
@@ -0,0 +1,86 @@
+package holdings
+
+import (
+	"testing"
+	"time"
+
+	"aagr.xyz/trades/record"
+)
+
+var (
+	aliceAccount = record.Account{Name: "ALICE-GIA", Currency: record.GBP, Owner: "ALICE"}
+	bobAccount   = record.Account{Name: "BOB-GIA", Currency: record.GBP, Owner: "BOB"}
+)
+
+// ownerTrade returns a trade like trade, in the account of an owner
+func ownerTrade(act record.Account, ts time.Time, action record.TransactionType, ticker string, quantity, total float64) *record.Record {
+	r := trade(ts, action, ticker, quantity, total)
+	r.Broker = act
+	return r
+}
+
+// spouseTransfer returns a SPOUSETRANSFEROUT of quantity shares of the ticker from one account to the other
+func spouseTransfer(from, to record.Account, ts time.Time, ticker string, quantity float64) *record.Record {
+	return &record.Record{
+		Timestamp:   ts,
+		Broker:      from,
+		Action:      record.SpouseTransferOut,
+		Ticker:      ticker,
+		Name:        ticker,
+		ShareCount:  quantity,
+		Currency:    record.GBP,
+		Description: to.Name,
+	}
+}
+
+func TestSpouseTransfer(t *testing.T) {
+	// The 400 shares move to the pool of bob at the average cost in the pool of alice, which is 4000
+	byOwner, transfers, err := ByOwner([]*record.Record{
+		ownerTrade(aliceAccount, day(2023, time.January, 10, 10), record.Buy, "ABC", 1000, 10000),
+		ownerTrade(bobAccount, day(2023, time.February, 1, 10), record.Buy, "ABC", 100, 2000),
+		spouseTransfer(aliceAccount, bobAccount, day(2023, time.June, 1, 10), "ABC", 400),
+		ownerTrade(bobAccount, day(2023, time.September, 1, 10), record.Sell, "ABC", 500, 7500),
+	})
+	if err != nil {
+		t.Fatalf("ByOwner() failed: %v", err)
+	}
+	if len(transfers) != 1 || transfers[0].Action != record.SpouseTransferIn || transfers[0].Broker.Owner != "BOB" ||
+		!near(transfers[0].ShareCount, 400) || !near(transfers[0].Total, 4000) {
+		t.Errorf("got transfers %v, want 400 shares in to BOB for 4000", transfers)
+	}
+	alice, bob := byOwner["ALICE"]["ABC"], byOwner["BOB"]["ABC"]
+	if alice == nil || bob == nil {
+		t.Fatalf("got holdings %v, want ABC for both ALICE and BOB", byOwner)
+	}
+	// There is no gain or loss for alice
+	checkDisposals(t, map[string]*Holding{"ABC": alice}, nil)
+	if p := alice.taxable.gbp; !near(p.quantity, 600) || !near(p.totalCost, 6000) {
+		t.Errorf("got pool %v of ALICE, want qty=600, totalCost=6000", p)
+	}
+	checkDisposals(t, map[string]*Holding{"ABC": bob}, []*Disposal{{
+		TaxYear: "2023-24", Quantity: 500, Proceeds: 7500, AllowableCost: 6000, Gain: 1500,
+		Matches: []*Acquisition{{Rule: PoolRule, Quantity: 500, Cost: 6000}},
+	}})
+}
+
+func TestSpouseTransferErrors(t *testing.T) {
+	aliceISA := record.Account{Name: "ALICE-ISA", Currency: record.GBP, CGTExempt: true, Owner: "ALICE"}
+	for _, tc := range []struct {
+		name string
+		to   record.Account
+	}{
+		{name: "same owner", to: aliceISA},
+		{name: "unknown account", to: record.Account{Name: "CAROL-GIA", Owner: "CAROL"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			records := []*record.Record{
+				ownerTrade(aliceAccount, day(2023, time.January, 10, 10), record.Buy, "ABC", 1000, 10000),
+				ownerTrade(aliceISA, day(2023, time.January, 10, 10), record.Buy, "XYZ", 10, 100),
+				spouseTransfer(aliceAccount, tc.to, day(2023, time.June, 1, 10), "ABC", 400),
+			}
+			if _, err := SpouseTransfers(records); err == nil {
+				t.Errorf("SpouseTransfers() succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package holdings
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+)
+
+// pensionAllowances is the annual allowance for the gross pension contributions of a person for each
+// tax year, ignoring the taper for high incomes. Add an entry here whenever a new tax year starts.
+var pensionAllowances = map[string]float64{
+	"2011-12": 50000,
+	"2012-13": 50000,
+	"2013-14": 50000,
+	"2014-15": 40000,
+	"2015-16": 40000,
+	"2016-17": 40000,
+	"2017-18": 40000,
+	"2018-19": 40000,
+	"2019-20": 40000,
+	"2020-21": 40000,
+	"2021-22": 40000,
+	"2022-23": 40000,
+	"2023-24": 60000,
+	"2024-25": 60000,
+	"2025-26": 60000,
+	"2026-27": 60000,
+}
+
+// carryForwardYears is the number of previous tax years whose unused annual allowance can be used
+const carryForwardYears = 3
+
+// PensionYear stores the pension contributions of a person in a tax year against the annual allowance, in GBP
+type PensionYear struct {
+	TaxYear string
+	// Contributions are paid in by the person, net of the TaxRelief added by the provider
+	Contributions, TaxRelief float64
+	Allowance                float64
+	// CarryForward is the unused allowance of the previous tax years, of which CarryForwardUsed
+	// covers the gross contributions above the allowance
+	CarryForward, CarryForwardUsed float64
+	// Unused is the allowance left to carry forward, and Excess is the gross contributions above both the
+	// allowance and the carry forward, which is charged to income tax
+	Unused, Excess float64
+}
+
+// Gross returns the contributions including the tax relief, which count towards the annual allowance
+func (p *PensionYear) Gross() float64 {
+	return p.Contributions + p.TaxRelief
+}
+
+// offsetTaxYear returns the tax year n years after the given one, like 2024-25 for 2023-24 and 1
+func offsetTaxYear(year string, n int) (string, error) {
+	start, err := strconv.Atoi(strings.Split(year, "-")[0])
+	if err != nil {
+		return "", fmt.Errorf("invalid tax year %q: %v", year, err)
+	}
+	return newTaxYear(start+n, start+n+1).name, nil
+}
+
+// pensionAmount returns the cash paid into a SIPP by the record in GBP
+func pensionAmount(r *record.Record) (float64, error) {
+	if r.Currency == record.GBP {
+		return r.ShareCount, nil
+	}
+	rate, err := db.GetForex(r.Timestamp, r.Currency)
+	if err != nil {
+		return 0.0, fmt.Errorf("cannot get forex: %v", err)
+	}
+	return r.ShareCount * rate, nil
+}
+
+// pensionContributions returns the contributions and tax relief paid into the SIPPs of the owner,
+// keyed by tax year
+func pensionContributions(records []*record.Record, owner string) map[string]*PensionYear {
+	res := make(map[string]*PensionYear)
+	for _, r := range recordsOfOwner(records, owner) {
+		if r.Action != record.PensionContribution && r.Action != record.TaxRelief {
+			continue
+		}
+		year := getTaxYear(r.Timestamp)
+		if year == "" {
+			log.Errorf("Cannot calculate tax year of pension record %v", r)
+			continue
+		}
+		amount, err := pensionAmount(r)
+		if err != nil {
+			log.Errorf("Cannot get GBP amount of pension record %v: %v", r, err)
+			continue
+		}
+		if _, ok := res[year]; !ok {
+			res[year] = &PensionYear{TaxYear: year}
+		}
+		if r.Action == record.PensionContribution {
+			res[year].Contributions += amount
+		} else {
+			res[year].TaxRelief += amount
+		}
+	}
+	return res
+}
+
+// PensionYears returns the pension contributions of the owner against the annual allowance for every tax year
+// from the first contribution, sorted by tax year. The unused allowance is carried forward from the previous
+// three tax years, oldest first, once the allowance of the tax year is used up. The owner is assumed to be a
+// member of a pension scheme only from the first contribution on.
+func PensionYears(records []*record.Record, owner string) ([]*PensionYear, error) {
+	byYear := pensionContributions(records, owner)
+	if len(byYear) == 0 {
+		return nil, nil
+	}
+	years := make([]string, 0, len(byYear))
+	for y := range byYear {
+		years = append(years, y)
+	}
+	sort.Strings(years)
+	var res []*PensionYear
+	for y := years[0]; y <= years[len(years)-1]; {
+		p, ok := byYear[y]
+		if !ok {
+			p = &PensionYear{TaxYear: y}
+		}
+		allowance, ok := pensionAllowances[y]
+		if !ok {
+			return nil, fmt.Errorf("no pension annual allowance known for tax year %s", y)
+		}
+		p.Allowance = allowance
+		// the unused allowance of the previous years, oldest first
+		from := len(res) - carryForwardYears
+		if from < 0 {
+			from = 0
+		}
+		previous := res[from:]
+		for _, prev := range previous {
+			p.CarryForward += prev.Unused
+		}
+		p.Unused = math.Max(0, p.Allowance-p.Gross())
+		excess := math.Max(0, p.Gross()-p.Allowance)
+		for _, prev := range previous {
+			used := math.Min(excess, prev.Unused)
+			prev.Unused -= used
+			p.CarryForwardUsed += used
+			excess -= used
+		}
+		p.Excess = excess
+		res = append(res, p)
+		next, err := offsetTaxYear(y, 1)
+		if err != nil {
+			return nil, err
+		}
+		y = next
+	}
+	return res, nil
+}
+
+// PensionAllowanceTable returns a table with the pension contributions of the owner against the annual
+// allowance for each tax year
+func PensionAllowanceTable(records []*record.Record, owner string) (table.Writer, error) {
+	years, err := PensionYears(records, owner)
+	if err != nil {
+		return nil, err
+	}
+	t := table.NewWriter()
+	t.SetTitle("Pension Annual Allowance")
+	t.SetStyle(table.StyleLight)
+	t.AppendHeader(table.Row{
+		"Tax Year", "Contributions (GBP)", "Tax Relief (GBP)", "Gross (GBP)", "Allowance (GBP)",
+		"Carry Forward (GBP)", "Carry Forward Used (GBP)", "Unused (GBP)", "Excess (GBP)", "Status",
+	})
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 2, Transformer: tf},
+		{Number: 3, Transformer: tf},
+		{Number: 4, Transformer: tf},
+		{Number: 5, Transformer: tf},
+		{Number: 6, Transformer: tf},
+		{Number: 7, Transformer: tf},
+		{Number: 8, Transformer: tf},
+		{Number: 9, Transformer: tf},
+	})
+	for _, p := range years {
+		status := "OK"
+		if p.Excess > epsilon {
+			status = "EXCEEDED"
+		}
+		t.AppendRow(table.Row{
+			p.TaxYear, p.Contributions, p.TaxRelief, p.Gross(), p.Allowance,
+			p.CarryForward, p.CarryForwardUsed, p.Unused, p.Excess, status,
+		})
+	}
+	return t, nil
+}
+
+// PensionRow stores the valuation of a SIPP against the cash paid into it, in GBP
+type PensionRow struct {
+	Name                     string
+	Contributions, TaxRelief float64
+	Value                    float64
+	Growth, GrowthPercentage float64
+}
+
+// PaidIn returns the contributions including the tax relief
+func (p *PensionRow) PaidIn() float64 {
+	return p.Contributions + p.TaxRelief
+}
+
+// PensionRows returns the valuation of every SIPP, whose growth is over the contributions and the tax relief
+// so that the tax relief does not count as performance
+func PensionRows(actRows map[record.Account][]*TickerRow, records []*record.Record) []*PensionRow {
+	byAccount := make(map[record.Account]*PensionRow)
+	for act, rows := range actRows {
+		if act.Type != record.SIPP_ACCOUNT {
+			continue
+		}
+		p := &PensionRow{Name: act.Name}
+		for _, r := range rows {
+			p.Value += r.GBPPriceMetrics.TotalValue
+		}
+		byAccount[act] = p
+	}
+	for _, r := range records {
+		p, ok := byAccount[r.Broker]
+		if !ok {
+			continue
+		}
+		if r.Action != record.PensionContribution && r.Action != record.TaxRelief {
+			continue
+		}
+		amount, err := pensionAmount(r)
+		if err != nil {
+			log.Errorf("Cannot get GBP amount of pension record %v: %v", r, err)
+			continue
+		}
+		if r.Action == record.PensionContribution {
+			p.Contributions += amount
+		} else {
+			p.TaxRelief += amount
+		}
+	}
+	var res []*PensionRow
+	for _, p := range byAccount {
+		p.Growth = p.Value - p.PaidIn()
+		if p.PaidIn() > 0.0 {
+			p.GrowthPercentage = p.Growth / p.PaidIn() * 100.0
+		}
+		res = append(res, p)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
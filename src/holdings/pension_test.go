@@ -0,0 +1,114 @@
+package holdings
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+)
+
+// contribution returns the records of paying gross into a SIPP during the tax year starting in the given
+// year, net of the 20% basic rate tax relief claimed by the provider
+func contribution(year int, gross float64) []*record.Record {
+	sipp := record.Account{Name: "SIPP", Currency: record.GBP, CGTExempt: true, Type: record.SIPP_ACCOUNT}
+	ts := day(year, time.July, 1, 10)
+	return []*record.Record{
+		{Timestamp: ts, Broker: sipp, Action: record.PensionContribution, Ticker: "GBP", ShareCount: gross * 0.8, Currency: record.GBP},
+		{Timestamp: ts, Broker: sipp, Action: record.TaxRelief, Ticker: "GBP", ShareCount: gross * 0.2, Currency: record.GBP},
+	}
+}
+
+func TestPensionCarryForward(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	for _, tc := range []struct {
+		name string
+		// gross contributions keyed by the year the tax year starts in
+		gross map[int]float64
+		// Unused is what is left after the following tax years carried it forward
+		want []*PensionYear
+	}{
+		{
+			name:  "within the allowance",
+			gross: map[int]float64{2023: 50000},
+			want: []*PensionYear{
+				{TaxYear: "2023-24", Allowance: 60000, Unused: 10000},
+			},
+		},
+		{
+			// The unused allowance is used oldest first, and the one of 2020-21 cannot be used after 2023-24
+			name: "carried forward oldest first",
+			gross: map[int]float64{
+				2020: 30000,
+				2021: 20000,
+				2022: 25000,
+				2023: 90000,
+				2024: 80000,
+			},
+			want: []*PensionYear{
+				{TaxYear: "2020-21", Allowance: 40000, Unused: 0},
+				{TaxYear: "2021-22", Allowance: 40000, CarryForward: 10000, Unused: 0},
+				{TaxYear: "2022-23", Allowance: 40000, CarryForward: 30000, Unused: 0},
+				{TaxYear: "2023-24", Allowance: 60000, CarryForward: 45000, CarryForwardUsed: 30000, Unused: 0},
+				{TaxYear: "2024-25", Allowance: 60000, CarryForward: 15000, CarryForwardUsed: 15000, Excess: 5000},
+			},
+		},
+		{
+			// A tax year without any contribution still has an allowance to carry forward
+			name: "gap year",
+			gross: map[int]float64{
+				2021: 40000,
+				2023: 120000,
+			},
+			want: []*PensionYear{
+				{TaxYear: "2021-22", Allowance: 40000},
+				{TaxYear: "2022-23", Allowance: 40000, Unused: 0},
+				{TaxYear: "2023-24", Allowance: 60000, CarryForward: 40000, CarryForwardUsed: 40000, Excess: 20000},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var records []*record.Record
+			for year, gross := range tc.gross {
+				records = append(records, contribution(year, gross)...)
+			}
+			years, err := PensionYears(records, "")
+			if err != nil {
+				t.Fatalf("PensionYears() failed: %v", err)
+			}
+			if len(years) != len(tc.want) {
+				t.Fatalf("got %d tax years, want %d", len(years), len(tc.want))
+			}
+			for i, w := range tc.want {
+				g := years[i]
+				start, _ := strconv.Atoi(w.TaxYear[:4])
+				if g.TaxYear != w.TaxYear || !near(g.Gross(), tc.gross[start]) || !near(g.Allowance, w.Allowance) ||
+					!near(g.CarryForward, w.CarryForward) || !near(g.CarryForwardUsed, w.CarryForwardUsed) ||
+					!near(g.Unused, w.Unused) || !near(g.Excess, w.Excess) {
+					t.Errorf("got %+v, want %+v", g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestPensionInForeignCurrency(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	ts := day(2023, time.July, 1, 10)
+	db.AddForex(ts, record.USD, 0.8)
+	sipp := record.Account{Name: "SIPP", Currency: record.USD, CGTExempt: true, Type: record.SIPP_ACCOUNT}
+	records := []*record.Record{
+		{Timestamp: ts, Broker: sipp, Action: record.PensionContribution, Ticker: "USD", ShareCount: 800, Currency: record.USD},
+		{Timestamp: ts, Broker: sipp, Action: record.TaxRelief, Ticker: "USD", ShareCount: 200, Currency: record.USD},
+	}
+	// The contributions are in GBP in both the allowance and the valuation of the SIPP
+	year, ok := pensionContributions(records, "")["2023-24"]
+	if !ok || !near(year.Contributions, 640) || !near(year.TaxRelief, 160) {
+		t.Errorf("got %+v for 2023-24, want contributions of 640 and tax relief of 160", year)
+	}
+	rows := PensionRows(map[record.Account][]*TickerRow{sipp: nil}, records)
+	if len(rows) != 1 || !near(rows[0].Contributions, 640) || !near(rows[0].TaxRelief, 160) {
+		t.Errorf("got %v, want 1 row with contributions of 640 and tax relief of 160", rows)
+	}
+}
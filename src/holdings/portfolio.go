@@ -3,7 +3,6 @@ package holdings
 import (
 	"fmt"
 	"math"
-	"strings"
 	"time"
 
 	"aagr.xyz/trades/db"
@@ -28,7 +27,19 @@ func presentQuote(ticker string, market *marketdata.Service) (*marketdata.Quote,
 	if meta.AssetType == record.FOREX_ASSET {
 		return &marketdata.Quote{RegularMarketPrice: 1.0}, nil
 	}
-	return market.GetQuote(ticker, meta.Currency, meta.Metadata)
+	// An inactive ticker is delisted or of negligible value, so it has no quote
+	if meta.State == db.Inactive {
+		return &marketdata.Quote{RegularMarketPrice: 0.0}, nil
+	}
+	quote, err := market.GetQuote(ticker, meta.Currency, meta.Metadata)
+	if err != nil || !meta.AssetType.IsBond() {
+		return quote, err
+	}
+	// A bond is quoted per 100 of nominal, while the quantity held is the nominal
+	return &marketdata.Quote{
+		RegularMarketPrice: quote.RegularMarketPrice / 100.0,
+		TodayPercentChange: quote.TodayPercentChange,
+	}, nil
 }
 
 func presentForex(currency record.Currency, market *marketdata.Service) (*marketdata.Quote, error) {
@@ -61,7 +72,8 @@ func AccountRows(byAccount map[record.Account]*Account, market *marketdata.Servi
 	for name, act := range byAccount {
 		var actRows []*TickerRow
 		for ticker, pos := range act.positions {
-			if math.Abs(pos.quantity-0.0) <= epsilon {
+			// Contracts have no market data, so their open positions are not valued
+			if math.Abs(pos.quantity-0.0) <= epsilon || isDerivative(ticker) {
 				continue
 			}
 			quote, err := presentQuote(ticker, market)
@@ -288,8 +300,15 @@ func PortfolioTable(holdings map[string]*Holding, market *marketdata.Service) (t
 	return t, nil
 }
 
-// CGT returns a string containing report for CGT along with a debug string
-func CGT(holdings map[string]*Holding) (map[string]table.Writer, string) {
+// CGT returns a table with the gains of every ticker for each tax year, except the foreign currencies
+// which are in FXTables
+func CGT(holdings map[string]*Holding) map[string]table.Writer {
+	securities, _ := splitFX(holdings)
+	return gainTables(securities, "Tax year %s")
+}
+
+// gainTables returns a table with the gains of every holding for each tax year, titled with the tax year
+func gainTables(holdings map[string]*Holding, title string) map[string]table.Writer {
 	var tables map[string]table.Writer = make(map[string]table.Writer)
 	var totalStats map[string]*stats = make(map[string]*stats)
 	years := maps.Keys(taxYears)
@@ -297,7 +316,7 @@ func CGT(holdings map[string]*Holding) (map[string]table.Writer, string) {
 	for _, ty := range years {
 		totalStats[ty] = &stats{}
 		t := table.NewWriter()
-		t.SetTitle(fmt.Sprintf("Tax year %s", ty))
+		t.SetTitle(fmt.Sprintf(title, ty))
 		t.SetStyle(table.StyleLight)
 		t.AppendHeader(table.Row{
 			"Ticker", "Disposed (GBP)", "Gain (GBP)",
@@ -325,17 +344,5 @@ func CGT(holdings map[string]*Holding) (map[string]table.Writer, string) {
 			"TOTAL", totalStats[ty].disposed, totalStats[ty].realizedGain,
 		})
 	}
-	return tables, debugCGT(holdings)
-}
-
-func debugCGT(holdings map[string]*Holding) string {
-	var sb strings.Builder
-	for ticker, h := range holdings {
-		if h.debug == "" {
-			continue
-		}
-		sb.WriteString(fmt.Sprintf("\n\n****** Ticker = %s ******\n", ticker))
-		sb.WriteString(h.debug)
-	}
-	return sb.String()
+	return tables
 }
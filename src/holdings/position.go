@@ -0,0 +1,61 @@
+package holdings
+
+import (
+	"fmt"
+	"sort"
+
+	"aagr.xyz/trades/record"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// spreadBets returns the closed spread bet positions keyed by tax year, sorted by the closing date.
+// Their profit is exempt from CGT, so they are in the exempt pool of the holding.
+func spreadBets(holdings map[string]*Holding) map[string][]*Disposal {
+	res := make(map[string][]*Disposal)
+	for _, h := range holdings {
+		for _, d := range h.cgtExempt.disposals {
+			if d.Record.Action != record.ClosedPosition {
+				continue
+			}
+			details, err := record.ParseClosedPosition(d.Record.Description)
+			if err != nil || details.Instrument != record.SPREAD_BET_ASSET {
+				continue
+			}
+			res[d.TaxYear] = append(res[d.TaxYear], d)
+		}
+	}
+	for _, disposals := range res {
+		sort.SliceStable(disposals, func(i, j int) bool {
+			return disposals[i].Record.Timestamp.Before(disposals[j].Record.Timestamp)
+		})
+	}
+	return res
+}
+
+// SpreadBetTables returns a table with the profit and loss of the closed spread bets for each tax year,
+// which is not part of the gains as spread bets are exempt from CGT
+func SpreadBetTables(holdings map[string]*Holding) map[string]table.Writer {
+	tables := make(map[string]table.Writer)
+	for ty, disposals := range spreadBets(holdings) {
+		t := table.NewWriter()
+		t.SetTitle(fmt.Sprintf("Exempt spread bet P&L in tax year %s", ty))
+		t.SetStyle(table.StyleLight)
+		t.AppendHeader(table.Row{
+			"Opened", "Closed", "Market", "Account", "Size", "Close Level", "P&L (GBP)",
+		})
+		t.SetColumnConfigs([]table.ColumnConfig{
+			{Number: 7, Transformer: tf, TransformerFooter: tf},
+		})
+		var total float64
+		for _, d := range disposals {
+			t.AppendRow(table.Row{
+				d.Matches[0].Date.Format("2006-01-02"), d.Record.Timestamp.Format("2006-01-02"), d.Ticker,
+				d.Record.Broker.Name, d.Quantity, d.Record.PricePerShare, d.Gain,
+			})
+			total += d.Gain
+		}
+		t.AppendFooter(table.Row{"TOTAL", "", "", "", "", "", total})
+		tables[ty] = t
+	}
+	return tables
+}
@@ -0,0 +1,47 @@
+package holdings
+
+import (
+	"testing"
+	"time"
+
+	"aagr.xyz/trades/record"
+)
+
+// closedPosition returns a CLOSEDPOSITION of the instrument with the given profit, negative for a loss
+func closedPosition(instrument record.AssetType, ts time.Time, market string, size, profit float64) *record.Record {
+	return &record.Record{
+		Timestamp:    ts,
+		Broker:       testAccount,
+		Action:       record.ClosedPosition,
+		Ticker:       market,
+		Name:         market,
+		ShareCount:   size,
+		Currency:     record.GBP,
+		ExchangeRate: 1.0,
+		Total:        profit,
+		Description:  (&record.ClosedPositionDetails{Instrument: instrument, Opened: ts.AddDate(0, -1, 0)}).String(),
+	}
+}
+
+func TestClosedPosition(t *testing.T) {
+	holdings, err := ByTicker([]*record.Record{
+		closedPosition(record.CFD_ASSET, day(2023, time.June, 1, 10), "FTSE 100", 2, -500),
+		closedPosition(record.SPREAD_BET_ASSET, day(2023, time.June, 2, 10), "DAX", 1, 300),
+	})
+	if err != nil {
+		t.Fatalf("ByTicker() failed: %v", err)
+	}
+	// A loss is an allowable cost without any proceeds
+	checkDisposals(t, map[string]*Holding{"FTSE 100": holdings["FTSE 100"]}, []*Disposal{{
+		TaxYear: "2023-24", Quantity: 2, Proceeds: 0, AllowableCost: 500, Gain: -500,
+		Matches: []*Acquisition{{Rule: ClosedPositionRule, Quantity: 2, Cost: 500}},
+	}})
+	// The profit of a spread bet is exempt, even in an account which is not
+	if h := holdings["DAX"]; len(h.taxable.disposals) != 0 {
+		t.Errorf("got taxable disposals %v of a spread bet, want none", h.taxable.disposals)
+	}
+	bets := spreadBets(holdings)
+	if len(bets) != 1 || len(bets["2023-24"]) != 1 || !near(bets["2023-24"][0].Gain, 300) {
+		t.Errorf("got spread bets %v, want the one of DAX with profit 300 in 2023-24", bets)
+	}
+}
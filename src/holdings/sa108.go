@@ -0,0 +1,159 @@
+package holdings
+
+import (
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// SA108Section stores the boxes of one kind of asset in the SA108 capital gains summary
+type SA108Section struct {
+	Name              string  `json:"name"`
+	Disposals         int     `json:"disposals"`
+	Proceeds          float64 `json:"disposal_proceeds"`
+	AllowableCosts    float64 `json:"allowable_costs"`
+	GainsBeforeLosses float64 `json:"gains_before_losses"`
+	LossesInYear      float64 `json:"losses_in_year"`
+}
+
+// SA108 stores the numbers needed to fill the SA108 capital gains summary of a tax year
+type SA108 struct {
+	TaxYear      string        `json:"tax_year"`
+	ListedShares *SA108Section `json:"listed_shares"`
+	// Cryptoassets are reported separately from the tax year 2023-24, nil if none were disposed
+	Cryptoassets *SA108Section `json:"cryptoassets,omitempty"`
+	// OtherProperty has the foreign currencies and the derivatives like CFDs, options and futures, along with
+	// the cryptoassets before the tax year 2023-24. It is nil if none were disposed.
+	OtherProperty *SA108Section `json:"other_property,omitempty"`
+	// Summary of the tax year across all the sections
+	TotalGains               float64 `json:"total_gains"`
+	TotalLosses              float64 `json:"total_losses"`
+	LossesBroughtForwardUsed float64 `json:"losses_brought_forward_used"`
+	LossesCarriedForward     float64 `json:"losses_carried_forward"`
+}
+
+// cryptoSectionYear is the first tax year with a separate section for the cryptoassets
+const cryptoSectionYear = "2023-24"
+
+func newSA108Section(name string, st *stats) *SA108Section {
+	return &SA108Section{
+		Name:              name,
+		Disposals:         st.disposals,
+		Proceeds:          st.proceeds,
+		AllowableCosts:    st.allowableCost,
+		GainsBeforeLosses: st.gains,
+		LossesInYear:      st.losses,
+	}
+}
+
+// splitAssets splits the holdings into the listed shares, the cryptoassets and the derivatives, which are in
+// different sections of SA108
+func splitAssets(holdings map[string]*Holding) (map[string]*Holding, map[string]*Holding, map[string]*Holding) {
+	shares, crypto, derivatives := make(map[string]*Holding), make(map[string]*Holding), make(map[string]*Holding)
+	for ticker, h := range holdings {
+		meta, err := db.TickerMeta(ticker)
+		switch {
+		case err == nil && meta.AssetType == record.CRYPTO_ASSET:
+			crypto[ticker] = h
+		case err == nil && meta.AssetType.IsDerivative():
+			derivatives[ticker] = h
+		default:
+			shares[ticker] = h
+		}
+	}
+	return shares, crypto, derivatives
+}
+
+// SA108Reports returns the SA108 numbers for every tax year with a disposal, sorted by tax year
+func SA108Reports(holdings map[string]*Holding, configs map[string]*TaxYearConfig) []*SA108 {
+	totals := yearTotals(holdings)
+	securities, other := splitFX(holdings)
+	shares, crypto, derivatives := splitAssets(securities)
+	maps.Copy(other, derivatives)
+	sharesTotals, cryptoTotals, otherTotals := yearTotals(shares), yearTotals(crypto), yearTotals(other)
+	liabilities, _ := Liabilities(holdings, configs)
+	byYear := make(map[string]*Liability)
+	for _, l := range liabilities {
+		byYear[l.TaxYear] = l
+	}
+	years := maps.Keys(totals)
+	slices.Sort(years)
+	var res []*SA108
+	for _, ty := range years {
+		st := totals[ty]
+		sharesSt, ok := sharesTotals[ty]
+		if !ok {
+			sharesSt = &stats{}
+		}
+		r := &SA108{
+			TaxYear:      ty,
+			ListedShares: newSA108Section("Listed shares and securities", sharesSt),
+			TotalGains:   st.gains,
+			TotalLosses:  st.losses,
+		}
+		otherSt, hasOther := otherTotals[ty]
+		if cryptoSt, ok := cryptoTotals[ty]; ok && ty >= cryptoSectionYear {
+			r.Cryptoassets = newSA108Section("Cryptoassets", cryptoSt)
+		} else if ok {
+			merged := &stats{}
+			if hasOther {
+				merged.add(otherSt)
+			}
+			merged.add(cryptoSt)
+			otherSt, hasOther = merged, true
+		}
+		if hasOther {
+			r.OtherProperty = newSA108Section("Other property, assets and gains", otherSt)
+		}
+		if l, ok := byYear[ty]; ok {
+			r.LossesBroughtForwardUsed = l.LossesUsed
+			r.LossesCarriedForward = l.LossesCarriedForward
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+// SA108Table returns a table laying out the SA108 boxes for every tax year
+func SA108Table(reports []*SA108) table.Writer {
+	t := table.NewWriter()
+	t.SetTitle("SA108 Capital Gains Summary")
+	t.SetStyle(table.StyleLight)
+	t.AppendHeader(table.Row{
+		"Tax Year", "Section", "Box", "Value",
+	})
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 1, AutoMerge: true},
+		{Number: 2, AutoMerge: true},
+		{Number: 4, Align: text.AlignRight},
+	})
+	for _, r := range reports {
+		sections := []*SA108Section{r.ListedShares}
+		if r.Cryptoassets != nil {
+			sections = append(sections, r.Cryptoassets)
+		}
+		if r.OtherProperty != nil {
+			sections = append(sections, r.OtherProperty)
+		}
+		for _, sec := range sections {
+			t.AppendRows([]table.Row{
+				{r.TaxYear, sec.Name, "Number of disposals", sec.Disposals},
+				{r.TaxYear, sec.Name, "Disposal proceeds", tf(sec.Proceeds)},
+				{r.TaxYear, sec.Name, "Allowable costs (including purchase price)", tf(sec.AllowableCosts)},
+				{r.TaxYear, sec.Name, "Gains in the year, before losses", tf(sec.GainsBeforeLosses)},
+				{r.TaxYear, sec.Name, "Losses in the year", tf(sec.LossesInYear)},
+			})
+		}
+		t.AppendRows([]table.Row{
+			{r.TaxYear, "Summary", "Total gains", tf(r.TotalGains)},
+			{r.TaxYear, "Summary", "Total losses of the year", tf(r.TotalLosses)},
+			{r.TaxYear, "Summary", "Losses brought forward and used in the year", tf(r.LossesBroughtForwardUsed)},
+			{r.TaxYear, "Summary", "Losses available to be carried forward", tf(r.LossesCarriedForward)},
+		})
+		t.AppendSeparator()
+	}
+	return t
+}
@@ -0,0 +1,70 @@
+package holdings
+
+import (
+	"testing"
+	"time"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+)
+
+// checkSection compares the number of disposals, the gains and the losses of a section of SA108
+func checkSection(t *testing.T, name string, got *SA108Section, disposals int, gains, losses float64) {
+	t.Helper()
+	if got == nil {
+		t.Errorf("%s: got no section, want %d disposals", name, disposals)
+		return
+	}
+	if got.Disposals != disposals || !near(got.GainsBeforeLosses, gains) || !near(got.LossesInYear, losses) {
+		t.Errorf("%s: got %+v, want %d disposals with gains %.2f and losses %.2f", name, got, disposals, gains, losses)
+	}
+}
+
+func TestSA108Sections(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	db.SetAssetType("BTC", record.CRYPTO_ASSET)
+	db.SetAssetType("FTSE 100", record.CFD_ASSET)
+	var records []*record.Record
+	records = append(records, realise("ABC", day(2023, time.June, 1, 10), 1000)...)
+	records = append(records, realise("BTC", day(2023, time.June, 2, 10), 2000)...)
+	records = append(records, closedPosition(record.CFD_ASSET, day(2023, time.June, 3, 10), "FTSE 100", 2, -500))
+	holdings, err := ByTicker(records)
+	if err != nil {
+		t.Fatalf("ByTicker() failed: %v", err)
+	}
+	reports := SA108Reports(holdings, map[string]*TaxYearConfig{"2023-24": {}})
+	if len(reports) != 1 || reports[0].TaxYear != "2023-24" {
+		t.Fatalf("got %d reports, want 1 for 2023-24", len(reports))
+	}
+	r := reports[0]
+	checkSection(t, "listed shares", r.ListedShares, 1, 1000, 0)
+	checkSection(t, "cryptoassets", r.Cryptoassets, 1, 2000, 0)
+	// A CFD is not a share, so it goes under other property
+	checkSection(t, "other property", r.OtherProperty, 1, 0, 500)
+	if !near(r.TotalGains, 3000) || !near(r.TotalLosses, 500) {
+		t.Errorf("got total gains %.2f and losses %.2f, want 3000 and 500", r.TotalGains, r.TotalLosses)
+	}
+}
+
+func TestSA108CryptoBefore2023(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	db.SetAssetType("BTC", record.CRYPTO_ASSET)
+	db.SetAssetType("FTSE 100", record.CFD_ASSET)
+	var records []*record.Record
+	records = append(records, realise("BTC", day(2022, time.June, 2, 10), 2000)...)
+	records = append(records, closedPosition(record.CFD_ASSET, day(2022, time.June, 3, 10), "FTSE 100", 2, -500))
+	holdings, err := ByTicker(records)
+	if err != nil {
+		t.Fatalf("ByTicker() failed: %v", err)
+	}
+	reports := SA108Reports(holdings, map[string]*TaxYearConfig{"2022-23": {}})
+	if len(reports) != 1 || reports[0].TaxYear != "2022-23" {
+		t.Fatalf("got %d reports, want 1 for 2022-23", len(reports))
+	}
+	r := reports[0]
+	// The cryptoassets have no section of their own before 2023-24
+	if r.Cryptoassets != nil {
+		t.Errorf("got cryptoassets %+v, want none", r.Cryptoassets)
+	}
+	checkSection(t, "other property", r.OtherProperty, 2, 2000, 500)
+}
@@ -0,0 +1,118 @@
+package holdings
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"aagr.xyz/trades/record"
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+)
+
+// SchemeIncome stores the employment income of shares acquired through an employee share scheme,
+// which needs to match the P60 of the tax year
+type SchemeIncome struct {
+	Date    time.Time
+	Ticker  string
+	Account string
+	Scheme  string
+	// Gross is the number of shares acquired, including the ones withheld for tax
+	Gross, Withheld float64
+	// PricePerShare is the income per share in Currency
+	PricePerShare float64
+	Currency      record.Currency
+	// Income and WithheldValue are in GBP
+	Income, WithheldValue float64
+}
+
+// Net returns the number of shares kept after the ones withheld for tax
+func (s *SchemeIncome) Net() float64 {
+	return s.Gross - s.Withheld
+}
+
+// shareSchemeIncome returns the employment income of the owner from share schemes keyed by tax year,
+// sorted by date
+func shareSchemeIncome(records []*record.Record, owner string) map[string][]*SchemeIncome {
+	res := make(map[string][]*SchemeIncome)
+	for _, r := range recordsOfOwner(records, owner) {
+		if r.Action != record.ShareSchemeIncome && r.Action != record.ESPPPurchase {
+			continue
+		}
+		year := getTaxYear(r.Timestamp)
+		if year == "" {
+			log.Errorf("Cannot calculate tax year of share scheme income record %v", r)
+			continue
+		}
+		s := &SchemeIncome{
+			Date:     r.Timestamp,
+			Ticker:   r.Ticker,
+			Account:  r.Broker.Name,
+			Gross:    r.ShareCount,
+			Currency: r.Currency,
+		}
+		switch r.Action {
+		case record.ShareSchemeIncome:
+			d, err := record.ParseShareScheme(r.Description)
+			if err != nil {
+				log.Errorf("Cannot parse share scheme income record %v: %v", r, err)
+				continue
+			}
+			s.Scheme = d.Scheme
+			s.Withheld = d.Withheld
+			s.PricePerShare = r.PricePerShare
+			s.Income = r.Total
+			s.WithheldValue = d.Withheld * r.PricePerShare * r.ExchangeRate
+		case record.ESPPPurchase:
+			d, err := record.ParseESPP(r.Description)
+			if err != nil {
+				log.Errorf("Cannot parse ESPP purchase record %v: %v", r, err)
+				continue
+			}
+			// The discount to the market value is the income
+			s.Scheme = "ESPP"
+			s.PricePerShare = r.PricePerShare - d.PurchasePrice
+			s.Income = r.ShareCount * s.PricePerShare * r.ExchangeRate
+		}
+		res[year] = append(res[year], s)
+	}
+	for _, incomes := range res {
+		sort.SliceStable(incomes, func(i, j int) bool {
+			return incomes[i].Date.Before(incomes[j].Date)
+		})
+	}
+	return res
+}
+
+// ShareSchemeTables returns a table with the employment income of the owner from share schemes
+// for each tax year
+func ShareSchemeTables(records []*record.Record, owner string) map[string]table.Writer {
+	tables := make(map[string]table.Writer)
+	for ty, incomes := range shareSchemeIncome(records, owner) {
+		t := table.NewWriter()
+		t.SetTitle(fmt.Sprintf("Share scheme income in tax year %s", ty))
+		t.SetStyle(table.StyleLight)
+		t.AppendHeader(table.Row{
+			"Date", "Ticker", "Account", "Scheme", "Gross Shares", "Withheld Shares", "Net Shares",
+			"Income Per Share", "Currency", "Employment Income (GBP)", "Withheld For Tax (GBP)",
+		})
+		t.SetColumnConfigs([]table.ColumnConfig{
+			{Number: 10, Transformer: tf, TransformerFooter: tf},
+			{Number: 11, Transformer: tf, TransformerFooter: tf},
+		})
+		var income, withheld float64
+		for _, s := range incomes {
+			t.AppendRow(table.Row{
+				s.Date.Format("2006-01-02"), s.Ticker, s.Account, s.Scheme, s.Gross, s.Withheld, s.Net(),
+				s.PricePerShare, s.Currency, s.Income, s.WithheldValue,
+			})
+			income += s.Income
+			withheld += s.WithheldValue
+		}
+		t.AppendFooter(table.Row{
+			"TOTAL", "", "", "", "", "", "", "", "", income, withheld,
+		})
+		tables[ty] = t
+	}
+	return tables
+}
@@ -0,0 +1,120 @@
+package holdings
+
+import (
+	"fmt"
+	"time"
+
+	"aagr.xyz/trades/record"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"golang.org/x/exp/maps"
+)
+
+// Simulation stores the outcome of a hypothetical SELL compared to the present state
+type Simulation struct {
+	Sell    *record.Record
+	TaxYear string
+	// Disposal is how the gain of the SELL would be calculated
+	Disposal *Disposal
+	// Gain of the tax year across all the taxable pools before and after the SELL
+	GainBefore, GainAfter float64
+	Warnings              []string
+}
+
+// GainDelta returns the change in the gain of the tax year because of the SELL
+func (s *Simulation) GainDelta() float64 {
+	return s.GainAfter - s.GainBefore
+}
+
+// Simulate calculates what happens if the given SELL is added to the records.
+// current stores the holdings calculated from the records as of now.
+func Simulate(current map[string]*Holding, sell *record.Record) (*Simulation, error) {
+	if sell.Action != record.Sell {
+		return nil, fmt.Errorf("can only simulate a SELL, got %s", sell.Action)
+	}
+	year := getTaxYear(sell.Timestamp)
+	if year == "" {
+		return nil, fmt.Errorf("cannot calculate tax year from record timestamp: %v", sell.Timestamp)
+	}
+	holding, ok := current[sell.Ticker]
+	if !ok {
+		return nil, fmt.Errorf("no records found for ticker %s", sell.Ticker)
+	}
+	// A gilt or a personal use currency is exempt from CGT in any account, just like its other records
+	exempt := exemptRecord(sell)
+	withSell := append(append([]*record.Record{}, holding.records...), exempt)
+	h, err := calculateInternal(sell.Ticker, withSell)
+	if err != nil {
+		return nil, fmt.Errorf("cannot calculate holding stats with the SELL: %v", err)
+	}
+	after := maps.Clone(current)
+	after[sell.Ticker] = h
+
+	res := &Simulation{
+		Sell:    sell,
+		TaxYear: year,
+	}
+	if st, ok := yearTotals(current)[year]; ok {
+		res.GainBefore = st.realizedGain
+	}
+	if st, ok := yearTotals(after)[year]; ok {
+		res.GainAfter = st.realizedGain
+	}
+	// Sells on the same day are aggregated, so look for the disposal on the day of the SELL
+	poolActive := h.taxable
+	if exempt.Broker.CGTExempt {
+		poolActive = h.cgtExempt
+	}
+	day := sell.Timestamp.Truncate(24 * time.Hour)
+	for _, d := range poolActive.disposals {
+		if d.Record.Timestamp.Truncate(24 * time.Hour).Equal(day) {
+			res.Disposal = d
+		}
+	}
+	if res.Disposal != nil && res.Disposal.Quantity > sell.ShareCount+epsilon {
+		res.Warnings = append(res.Warnings, fmt.Sprintf(
+			"The SELL is aggregated with other sells of %s on the same day, the disposal shows all of them", sell.Ticker))
+	}
+	if sell.Broker.CGTExempt {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("Account %s is CGT exempt, the gain is not taxable", sell.Broker.Name))
+		return res, nil
+	}
+	if exempt.Broker.CGTExempt {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("%s is exempt from CGT, the gain is not taxable", sell.Ticker))
+		return res, nil
+	}
+	res.Warnings = append(res.Warnings, fmt.Sprintf(
+		"Buying %s again in a taxable account on or before %s would be matched against this SELL under the bed and breakfast rule, instead of the pool",
+		sell.Ticker, sell.Timestamp.AddDate(0, 0, 30).Format("2006-01-02")))
+	if res.Disposal != nil {
+		for _, m := range res.Disposal.Matches {
+			if m.Rule == SameDayRule {
+				res.Warnings = append(res.Warnings, fmt.Sprintf(
+					"%f shares are matched against the purchase of %s on the same day", m.Quantity, sell.Ticker))
+			}
+		}
+	}
+	return res, nil
+}
+
+// SimulationTable returns a table with the outcome of a simulated SELL
+func SimulationTable(s *Simulation) table.Writer {
+	t := table.NewWriter()
+	t.SetTitle(fmt.Sprintf("SELL %f %s @ %f %s in %s", s.Sell.ShareCount, s.Sell.Ticker,
+		s.Sell.PricePerShare, s.Sell.Currency, s.Sell.Broker.Name))
+	t.SetStyle(table.StyleLight)
+	t.AppendHeader(table.Row{"", "Value"})
+	t.AppendRow(table.Row{"Tax Year", s.TaxYear})
+	t.AppendRow(table.Row{"Proceeds (GBP)", tf(s.Sell.Total)})
+	if s.Disposal != nil {
+		t.AppendRow(table.Row{"Allowable Cost (GBP)", tf(s.Disposal.AllowableCost)})
+		t.AppendRow(table.Row{"Gain (GBP)", tf(s.Disposal.Gain)})
+	}
+	t.AppendRow(table.Row{"Tax Year Gain Before (GBP)", tf(s.GainBefore)})
+	t.AppendRow(table.Row{"Tax Year Gain After (GBP)", tf(s.GainAfter)})
+	t.AppendRow(table.Row{"Gain Delta (GBP)", tf(s.GainDelta())})
+	t.AppendSeparator()
+	for _, w := range s.Warnings {
+		t.AppendRow(table.Row{"Warning", w})
+	}
+	return t
+}
@@ -0,0 +1,43 @@
+package holdings
+
+import (
+	"testing"
+	"time"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+)
+
+func TestSimulate(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	db.SetAssetType("TG25", record.GILT_ASSET)
+	for _, tc := range []struct {
+		name      string
+		ticker    string
+		wantDelta float64
+	}{
+		{name: "shares", ticker: "ABC", wantDelta: 500},
+		// A gilt is exempt from CGT even in a taxable account
+		{name: "gilt", ticker: "TG25"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			holdings, err := ByTicker([]*record.Record{
+				trade(day(2023, time.June, 1, 10), record.Buy, tc.ticker, 1000, 10000),
+			})
+			if err != nil {
+				t.Fatalf("ByTicker() failed: %v", err)
+			}
+			sell := trade(day(2023, time.August, 1, 10), record.Sell, tc.ticker, 500, 5500)
+			sim, err := Simulate(holdings, sell)
+			if err != nil {
+				t.Fatalf("Simulate() failed: %v", err)
+			}
+			if !near(sim.GainDelta(), tc.wantDelta) {
+				t.Errorf("got gain delta %.2f, want %.2f", sim.GainDelta(), tc.wantDelta)
+			}
+			if sim.Disposal == nil || !near(sim.Disposal.Gain, 500) {
+				t.Errorf("got disposal %v, want a gain of 500", sim.Disposal)
+			}
+		})
+	}
+}
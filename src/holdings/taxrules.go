@@ -0,0 +1,112 @@
+package holdings
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"aagr.xyz/trades/proto/statementspb"
+)
+
+var taxYearRegexp = regexp.MustCompile(`^\d{4}-\d{2}$`)
+
+// taxRules stores the CGT rates and allowances for a given tax year
+type taxRules struct {
+	annualExemptAmount float64
+	// basicRateBand is the size of the basic rate band for income tax, gains which fall within
+	// what is left of it after the taxable income are charged at the basic rate.
+	basicRateBand         float64
+	basicRate, higherRate float64
+	// rateChanges are the rates for disposals from a day part-way through the tax year,
+	// the rates above being for the disposals before the first change
+	rateChanges []*ratePeriod
+}
+
+// ratePeriod stores the CGT rates for the disposals from a given day
+type ratePeriod struct {
+	from                  time.Time
+	basicRate, higherRate float64
+}
+
+// periods returns the rate periods of the tax year, the first one having no start day
+func (r *taxRules) periods() []*ratePeriod {
+	return append([]*ratePeriod{{basicRate: r.basicRate, higherRate: r.higherRate}}, r.rateChanges...)
+}
+
+// periodOf returns the index of the rate period the day of a disposal falls in
+func (r *taxRules) periodOf(ts time.Time) int {
+	res := 0
+	for i, p := range r.periods() {
+		if !ts.Before(p.from) {
+			res = i
+		}
+	}
+	return res
+}
+
+// cgtRules is the table of CGT rules for each tax year. The rates are the ones for gains on
+// assets other than residential property and carried interest.
+// Add an entry here whenever a new tax year starts.
+var cgtRules = map[string]*taxRules{
+	"2011-12": {annualExemptAmount: 10600, basicRateBand: 35000, basicRate: 0.18, higherRate: 0.28},
+	"2012-13": {annualExemptAmount: 10600, basicRateBand: 34370, basicRate: 0.18, higherRate: 0.28},
+	"2013-14": {annualExemptAmount: 10900, basicRateBand: 32010, basicRate: 0.18, higherRate: 0.28},
+	"2014-15": {annualExemptAmount: 11000, basicRateBand: 31865, basicRate: 0.18, higherRate: 0.28},
+	"2015-16": {annualExemptAmount: 11100, basicRateBand: 31785, basicRate: 0.18, higherRate: 0.28},
+	"2016-17": {annualExemptAmount: 11100, basicRateBand: 32000, basicRate: 0.10, higherRate: 0.20},
+	"2017-18": {annualExemptAmount: 11300, basicRateBand: 33500, basicRate: 0.10, higherRate: 0.20},
+	"2018-19": {annualExemptAmount: 11700, basicRateBand: 34500, basicRate: 0.10, higherRate: 0.20},
+	"2019-20": {annualExemptAmount: 12000, basicRateBand: 37500, basicRate: 0.10, higherRate: 0.20},
+	"2020-21": {annualExemptAmount: 12300, basicRateBand: 37500, basicRate: 0.10, higherRate: 0.20},
+	"2021-22": {annualExemptAmount: 12300, basicRateBand: 37700, basicRate: 0.10, higherRate: 0.20},
+	"2022-23": {annualExemptAmount: 12300, basicRateBand: 37700, basicRate: 0.10, higherRate: 0.20},
+	"2023-24": {annualExemptAmount: 6000, basicRateBand: 37700, basicRate: 0.10, higherRate: 0.20},
+	// The rates changed to 18% and 24% for disposals from 30 October 2024
+	"2024-25": {annualExemptAmount: 3000, basicRateBand: 37700, basicRate: 0.10, higherRate: 0.20,
+		rateChanges: []*ratePeriod{
+			{from: time.Date(2024, time.October, 30, 0, 0, 0, 0, time.UTC), basicRate: 0.18, higherRate: 0.24},
+		},
+	},
+	"2025-26": {annualExemptAmount: 3000, basicRateBand: 37700, basicRate: 0.18, higherRate: 0.24},
+	"2026-27": {annualExemptAmount: 3000, basicRateBand: 37700, basicRate: 0.18, higherRate: 0.24},
+}
+
+func rulesForTaxYear(year string) (*taxRules, error) {
+	rules, ok := cgtRules[year]
+	if !ok {
+		return nil, fmt.Errorf("no CGT rules known for tax year %s", year)
+	}
+	return rules, nil
+}
+
+// TaxYearConfig stores the personal details of a tax year needed to calculate the CGT liability
+type TaxYearConfig struct {
+	// TaxableIncome is the income after personal allowance
+	TaxableIncome float64
+	// LossesClaimed is set once the losses of the tax year have been reported to HMRC
+	LossesClaimed bool
+}
+
+// TaxYearsFromProto returns the config for each tax year keyed by the owner and then by the name of the tax year
+func TaxYearsFromProto(years []*statementspb.TaxYear) (map[string]map[string]*TaxYearConfig, error) {
+	res := make(map[string]map[string]*TaxYearConfig)
+	for _, ty := range years {
+		if !taxYearRegexp.MatchString(ty.GetName()) {
+			return nil, fmt.Errorf("invalid tax year name %q, want something like 2023-24", ty.GetName())
+		}
+		if _, ok := res[ty.GetOwner()]; !ok {
+			res[ty.GetOwner()] = make(map[string]*TaxYearConfig)
+		}
+		if _, ok := res[ty.GetOwner()][ty.GetName()]; ok {
+			return nil, fmt.Errorf("tax year %s is configured twice for owner %q", ty.GetName(), ty.GetOwner())
+		}
+		if ty.GetTaxableIncome() < 0.0 {
+			return nil, fmt.Errorf("taxable income of tax year %s cannot be negative", ty.GetName())
+		}
+		res[ty.GetOwner()][ty.GetName()] = &TaxYearConfig{
+			TaxableIncome: ty.GetTaxableIncome(),
+			LossesClaimed: ty.GetLossesClaimed(),
+		}
+	}
+	return res, nil
+}
@@ -2,11 +2,13 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"path"
 
 	"aagr.xyz/trades/config"
 	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/holdings"
 	"aagr.xyz/trades/marketdata"
 	"aagr.xyz/trades/parser"
 	"aagr.xyz/trades/proto/statementspb"
@@ -24,6 +26,10 @@ var (
 	transactionsFile   = flag.String("transactions_file", "", "The file for merged transactions")
 	configFile         = flag.String("config_file", "", "The file for parsing config textproto")
 	port               = flag.Int("port", 0, "The port to run the web server on")
+	simulateTicker     = flag.String("simulate_ticker", "", "If set, print the outcome of selling this ticker today and exit")
+	simulateQuantity   = flag.Float64("simulate_quantity", 0, "The quantity to sell for the simulation")
+	simulatePrice      = flag.Float64("simulate_price", 0, "The price to sell at for the simulation, defaults to the present price")
+	simulateAccount    = flag.String("simulate_account", "", "The account to sell from for the simulation")
 	username, password string
 )
 
@@ -56,8 +62,9 @@ func main() {
 	market := marketdata.NewService(map[marketdata.Source]marketdata.Backend{
 		marketdata.YAHOO: yc,
 	})
-	db.InitDB(*rootDir)
+	db.InitDB(*rootDir, market)
 	var sts []*statements.Statement
+	var taxYears map[string]map[string]*holdings.TaxYearConfig
 	if *configFile != "" {
 		b, err := os.ReadFile(*configFile)
 		if err != nil {
@@ -72,6 +79,10 @@ func main() {
 			log.Fatalf("cannot parse the statements config: %v", err)
 		}
 		sts = append(sts, parsed...)
+		taxYears, err = holdings.TaxYearsFromProto(cfg.GetTaxYears())
+		if err != nil {
+			log.Fatalf("cannot parse the tax years config: %v", err)
+		}
 	}
 	if *transactionsFile != "" {
 		sts = append(sts, statements.New(parser.NewDefault(), "", []string{*transactionsFile}))
@@ -87,11 +98,26 @@ func main() {
 		Auth:       server.NewAuthorization(username, password),
 		Market:     market,
 		Static:     static,
+		TaxYears:   taxYears,
 	}
 	srv, err := server.New(cfg)
 	if err != nil {
 		log.Fatalf("cannot create a new server: %v", err)
 	}
+	if *simulateTicker != "" {
+		if err := srv.Update(); err != nil {
+			log.Fatalf("cannot update server state: %v", err)
+		}
+		sim, err := srv.Simulate(*simulateTicker, *simulateAccount, *simulateQuantity, *simulatePrice)
+		if err != nil {
+			log.Fatalf("cannot simulate the sell: %v", err)
+		}
+		fmt.Println(holdings.SimulationTable(sim).Render())
+		if sim.Disposal != nil {
+			fmt.Println(holdings.MatchTable(sim.Disposal).Render())
+		}
+		return
+	}
 	if err := srv.Run(*port); err != nil {
 		log.Fatalf("cannot run server: %v", err)
 	}
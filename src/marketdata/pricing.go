@@ -16,7 +16,7 @@ var (
 )
 
 type Backend interface {
-	GuessTicker(symbol string, currency record.Currency) (string, error)
+	GuessTicker(symbol string, currency record.Currency, assetType record.AssetType) (string, error)
 	QueryMetadata(ticker string) (*SourceMetadata, error)
 	GetQuote(ticker string, currency record.Currency) (*Quote, error)
 	GetForex(currency record.Currency) (float64, error)
@@ -148,7 +148,7 @@ func (s *Service) GetForex(currency record.Currency) (float64, error) {
 
 // func Search(symbol string) (*Quote, error) {}
 
-func (s *Service) Metadata(symbol string, currency record.Currency, old map[Source]*SourceMetadata) (map[Source]*SourceMetadata, error) {
+func (s *Service) Metadata(symbol string, currency record.Currency, assetType record.AssetType, old map[Source]*SourceMetadata) (map[Source]*SourceMetadata, error) {
 	var res = make(map[Source]*SourceMetadata)
 	for src, b := range s.backends {
 		md := old[src]
@@ -156,7 +156,7 @@ func (s *Service) Metadata(symbol string, currency record.Currency, old map[Sour
 			md = &SourceMetadata{}
 		}
 		if md.Ticker == "" {
-			t, err := b.GuessTicker(symbol, currency)
+			t, err := b.GuessTicker(symbol, currency, assetType)
 			if err != nil {
 				return nil, fmt.Errorf("cannot get ticker: %v", err)
 			}
@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"aagr.xyz/trades/record"
+	log "github.com/sirupsen/logrus"
+)
+
+type coinbaseParser struct {
+	broker record.Account
+}
+
+// NewCoinbase returns a parser for the transaction history CSV of Coinbase, with the lines
+// before the header removed.
+func NewCoinbase(act record.Account) (*coinbaseParser, error) {
+	if act.Currency != record.GBP {
+		return nil, fmt.Errorf("Coinbase parser works with GBP currency, got %s", act.Currency)
+	}
+	return &coinbaseParser{broker: act}, nil
+}
+
+func (p *coinbaseParser) ValidateHeader(contents []string) error {
+	want := map[int]string{
+		1:  "Timestamp",
+		2:  "Transaction Type",
+		3:  "Asset",
+		4:  "Quantity Transacted",
+		5:  "Price Currency",
+		7:  "Subtotal",
+		8:  "Total (inclusive of fees and/or spread)",
+		9:  "Fees and/or Spread",
+		10: "Notes",
+	}
+	return headerMatches(want, contents)
+}
+
+// coinbaseAmount parses an amount like "-£1,234.56"
+func coinbaseAmount(s string) (float64, error) {
+	s = strings.NewReplacer("£", "", "$", "", "€", "", ",", "").Replace(strings.TrimSpace(s))
+	if s == "" {
+		return 0.0, nil
+	}
+	val, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0.0, fmt.Errorf("cannot convert %v to amount as float: %v", s, err)
+	}
+	return math.Abs(val), nil
+}
+
+func (p *coinbaseParser) ToRecord(contents []string) ([]*record.Record, error) {
+	ts, err := time.Parse("2006-01-02 15:04:05 MST", contents[1])
+	if err != nil {
+		ts, err = time.Parse(time.RFC3339, contents[1])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse timestamp %s: %v", contents[1], err)
+		}
+	}
+	asset := strings.ToUpper(contents[3])
+	quantity, err := coinbaseAmount(contents[4])
+	if err != nil {
+		return nil, fmt.Errorf("cannot get quantity: %v", err)
+	}
+	switch contents[2] {
+	case "Deposit", "Withdrawal":
+		if record.NewCurrency(asset) != record.GBP {
+			log.Warningf("Coinbase %s of %s is not supported, add it manually: %v", contents[2], asset, contents)
+			return nil, nil
+		}
+		action := record.CashIn
+		if contents[2] == "Withdrawal" {
+			action = record.CashOut
+		}
+		return []*record.Record{{
+			Timestamp:  ts,
+			Broker:     p.broker,
+			Action:     action,
+			Ticker:     string(record.GBP),
+			ShareCount: quantity,
+			Currency:   record.GBP,
+		}}, nil
+	case "Buy", "Sell", "Advanced Trade Buy", "Advanced Trade Sell", "Convert":
+	default:
+		log.Warningf("Coinbase transaction type %q is not supported, add it manually: %v", contents[2], contents)
+		return nil, nil
+	}
+	if contents[5] != string(record.GBP) {
+		return nil, fmt.Errorf("Coinbase parser works with prices in GBP, got %s", contents[5])
+	}
+	total, err := coinbaseAmount(contents[8])
+	if err != nil {
+		return nil, fmt.Errorf("cannot get total: %v", err)
+	}
+	fee, err := coinbaseAmount(contents[9])
+	if err != nil {
+		return nil, fmt.Errorf("cannot get fee: %v", err)
+	}
+	switch contents[2] {
+	case "Buy", "Advanced Trade Buy":
+		// the total includes the fee
+		return []*record.Record{cryptoRecord(p.broker, ts, record.Buy, asset, quantity, total-fee, fee)}, nil
+	case "Sell", "Advanced Trade Sell":
+		// the total is net of the fee
+		return []*record.Record{cryptoRecord(p.broker, ts, record.Sell, asset, quantity, total+fee, fee)}, nil
+	}
+	// The notes are "Converted <quantity> <asset> to <quantity> <asset>"
+	notes := strings.Fields(contents[10])
+	if len(notes) != 6 || notes[0] != "Converted" || notes[3] != "to" {
+		return nil, fmt.Errorf("cannot parse convert notes %q", contents[10])
+	}
+	bought, err := coinbaseAmount(notes[4])
+	if err != nil {
+		return nil, fmt.Errorf("cannot get quantity converted to: %v", err)
+	}
+	return cryptoExchange(p.broker, ts, asset, quantity, strings.ToUpper(notes[5]), bought, total, fee), nil
+}
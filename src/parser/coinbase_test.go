@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"testing"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+)
+
+func TestCoinbase(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	p, err := NewCoinbase(record.Account{Name: "COINBASE", Currency: record.GBP})
+	if err != nil {
+		t.Fatalf("NewCoinbase() failed: %v", err)
+	}
+	header := []string{
+		"ID", "Timestamp", "Transaction Type", "Asset", "Quantity Transacted", "Price Currency",
+		"Price at Transaction", "Subtotal", "Total (inclusive of fees and/or spread)", "Fees and/or Spread", "Notes",
+	}
+	if err := p.ValidateHeader(header); err != nil {
+		t.Fatalf("ValidateHeader() failed: %v", err)
+	}
+	for _, tc := range []struct {
+		name     string
+		contents []string
+		want     []*record.Record
+	}{
+		{
+			// The total includes the fee
+			name:     "buy",
+			contents: []string{"1", "2023-06-01 10:00:00 UTC", "Buy", "BTC", "0.01", "GBP", "£30,000.00", "£300.00", "£305.00", "£5.00", ""},
+			want: []*record.Record{
+				{Action: record.Buy, Ticker: "BTC", ShareCount: 0.01, Total: 305, Commission: 5},
+			},
+		},
+		{
+			// The total is net of the fee
+			name:     "sell",
+			contents: []string{"2", "2023-06-01 10:00:00 UTC", "Sell", "BTC", "-0.01", "GBP", "£30,000.00", "£300.00", "£295.00", "£5.00", ""},
+			want: []*record.Record{
+				{Action: record.Sell, Ticker: "BTC", ShareCount: 0.01, Total: 295, Commission: 5},
+			},
+		},
+		{
+			// A convert disposes of the ETH, whose fee is allowable, and acquires the BTC for what is left
+			name: "convert",
+			contents: []string{"3", "2023-06-01T10:00:00Z", "Convert", "ETH", "-1", "GBP", "£1,500.00", "£1,485.00", "£1,500.00", "£15.00",
+				"Converted 1 ETH to 0.0495 BTC"},
+			want: []*record.Record{
+				{Action: record.Sell, Ticker: "ETH", ShareCount: 1, Total: 1485, Commission: 15},
+				{Action: record.Buy, Ticker: "BTC", ShareCount: 0.0495, Total: 1485},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := p.ToRecord(tc.contents)
+			if err != nil {
+				t.Fatalf("ToRecord() failed: %v", err)
+			}
+			checkTrades(t, got, tc.want)
+		})
+	}
+}
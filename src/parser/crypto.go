@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"time"
+
+	"aagr.xyz/trades/record"
+)
+
+// cryptoRecord returns a BUY or SELL of a cryptoasset. As the cryptoasset is itself a currency, the record
+// is always in GBP. value is the GBP value of the quantity traded, before the fee in GBP.
+func cryptoRecord(act record.Account, ts time.Time, action record.TransactionType, asset string, quantity, value, fee float64) *record.Record {
+	r := &record.Record{
+		Timestamp:     ts,
+		Broker:        act,
+		Action:        action,
+		Ticker:        asset,
+		Name:          asset,
+		ShareCount:    quantity,
+		PricePerShare: value / quantity,
+		Currency:      record.GBP,
+		ExchangeRate:  1.0,
+		Commission:    fee,
+		AssetType:     record.CRYPTO_ASSET,
+	}
+	if action == record.Sell {
+		r.Total = value - fee
+	} else {
+		r.Total = value + fee
+	}
+	return r
+}
+
+// cryptoExchange returns the records of a crypto to crypto trade, which is a disposal of the cryptoasset sold
+// and an acquisition of the one bought. value is the GBP value of the cryptoasset sold, including the fee.
+// The fee is allowable against the disposal, so the cryptoasset bought costs the value less the fee.
+func cryptoExchange(act record.Account, ts time.Time, sold string, soldQuantity float64, bought string, boughtQuantity, value, fee float64) []*record.Record {
+	return []*record.Record{
+		cryptoRecord(act, ts, record.Sell, sold, soldQuantity, value, fee),
+		cryptoRecord(act, ts, record.Buy, bought, boughtQuantity, value-fee, 0.0),
+	}
+}
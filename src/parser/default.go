@@ -33,17 +33,90 @@ func (p *defaultParser) ValidateHeader(contents []string) error {
 		12: "Total",
 		13: "Description",
 	}
-	return headerMatches(want, contents)
+	if err := headerMatches(want, contents); err != nil {
+		return err
+	}
+	// The columns after the description are optional, but the ones present have to be in order,
+	// otherwise an unrelated column would be read as one of them
+	optional := []string{
+		14: "Account.Owner",
+		15: "Account.PersonalUseFX",
+		16: "Multiplier",
+		17: "AccruedInterest",
+		18: "Account.Type",
+		19: "AssetType",
+	}
+	for idx := 14; idx < len(optional) && idx < len(contents); idx++ {
+		if err := headerMatches(map[int]string{idx: optional[idx]}, contents); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (p *defaultParser) ToRecord(contents []string) ([]*record.Record, error) {
 	action := record.NewTransactionType(contents[4])
 	if action.IsMetadataEvent() {
+		if action == record.Takeover {
+			if _, err := record.ParseTakeover(contents[13]); err != nil {
+				return nil, fmt.Errorf("cannot parse takeover: %v", err)
+			}
+		}
+		if action == record.Split {
+			if _, err := record.ParseSplit(contents[13]); err != nil {
+				return nil, fmt.Errorf("cannot parse split: %v", err)
+			}
+		}
+		if action == record.NegligibleValue {
+			if _, err := record.ParseNegligibleValue(contents[13]); err != nil {
+				return nil, fmt.Errorf("cannot parse negligible value claim: %v", err)
+			}
+		}
+		if action == record.Demerger {
+			if _, err := record.ParseDemerger(contents[13]); err != nil {
+				return nil, fmt.Errorf("cannot parse demerger: %v", err)
+			}
+		}
 		return p.metadataRecord(contents)
 	} else if action.IsCashEvent() {
 		return p.cashRecord(contents)
-	} else if action.IsDividend() {
+	} else if action.IsDividend() || action == record.Interest {
+		// interest is cash received, just like a dividend
 		return p.divdendRecord(contents)
+	} else if action == record.CostAdjustment {
+		if _, err := record.ParseCostAdjustment(contents[13]); err != nil {
+			return nil, fmt.Errorf("cannot parse cost adjustment: %v", err)
+		}
+		// cost adjustments are cash received, just like a dividend
+		return p.divdendRecord(contents)
+	} else if action == record.ExcessReportableIncome {
+		return p.eriRecord(contents)
+	} else if action == record.ShareSchemeIncome {
+		d, err := record.ParseShareScheme(contents[13])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse share scheme: %v", err)
+		}
+		if quantity, err := strconv.ParseFloat(contents[7], 64); err == nil && d.Withheld > quantity {
+			return nil, fmt.Errorf("more shares withheld than acquired: %v", contents)
+		}
+	} else if action == record.ESPPPurchase {
+		d, err := record.ParseESPP(contents[13])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse ESPP purchase: %v", err)
+		}
+		if price, err := strconv.ParseFloat(contents[8], 64); err == nil && d.PurchasePrice > price {
+			return nil, fmt.Errorf("ESPP purchase price is more than the market value: %v", contents)
+		}
+	} else if action == record.ClosedPosition {
+		if _, err := record.ParseClosedPosition(contents[13]); err != nil {
+			return nil, fmt.Errorf("cannot parse closed position: %v", err)
+		}
+	} else if action == record.Exercise {
+		if _, err := record.ParseExercise(contents[13]); err != nil {
+			return nil, fmt.Errorf("cannot parse exercise: %v", err)
+		}
+	} else if action == record.SpouseTransferIn {
+		return nil, fmt.Errorf("%s records are generated from %s records, remove it: %v", action, record.SpouseTransferOut, contents)
 	} else if action.IsUnknown() {
 		return nil, fmt.Errorf("unknown transaction type: %v", contents)
 	}
@@ -91,6 +164,23 @@ func (p *defaultParser) ToRecord(contents []string) ([]*record.Record, error) {
 	if err != nil {
 		return nil, fmt.Errorf("cannot convert %v to total as float: %v", contents[12], err)
 	}
+	// Multiplier and AccruedInterest are optional columns
+	for idx, val := range map[int]*float64{16: &r.Multiplier, 17: &r.AccruedInterest} {
+		if len(contents) <= idx || contents[idx] == "" {
+			continue
+		}
+		*val, err = strconv.ParseFloat(contents[idx], 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %v to float: %v", contents[idx], err)
+		}
+	}
+	// AssetType is an optional column, needed for the tickers like gilts which are not equities
+	if len(contents) > 19 && contents[19] != "" {
+		r.AssetType, err = record.NewAssetType(contents[19])
+		if err != nil {
+			return nil, fmt.Errorf("cannot get asset type: %v", err)
+		}
+	}
 	return []*record.Record{r}, nil
 }
 
@@ -120,10 +210,36 @@ func (p *defaultParser) account(contents []string) (*record.Account, error) {
 	if string(curr) == "" {
 		return nil, fmt.Errorf("account.currency of record cannot be nil: %v", contents)
 	}
+	// Account.Owner is an optional column
+	var owner string
+	if len(contents) > 14 {
+		owner = strings.TrimSpace(contents[14])
+	}
+	// Account.PersonalUseFX is an optional column
+	var personalUseFX bool
+	if len(contents) > 15 && contents[15] != "" {
+		boolVal, err := strconv.ParseBool(contents[15])
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %q to bool: %v", contents[15], err)
+		}
+		personalUseFX = boolVal
+	}
+	// Account.Type is an optional column
+	var actType string
+	if len(contents) > 18 {
+		actType = contents[18]
+	}
+	t, err := record.NewAccountType(actType)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get account type: %v", err)
+	}
 	return &record.Account{
-		Name:      strings.ToUpper(contents[1]),
-		Currency:  curr,
-		CGTExempt: cgtExempt,
+		Name:          strings.ToUpper(contents[1]),
+		Currency:      curr,
+		CGTExempt:     cgtExempt || t.IsWrapper(),
+		Owner:         owner,
+		PersonalUseFX: personalUseFX,
+		Type:          t,
 	}, nil
 }
 func (p *defaultParser) cashRecord(contents []string) ([]*record.Record, error) {
@@ -181,3 +297,40 @@ func (p *defaultParser) divdendRecord(contents []string) ([]*record.Record, erro
 	r.Total = r.ShareCount * r.PricePerShare * r.ExchangeRate
 	return []*record.Record{r}, nil
 }
+
+func (p *defaultParser) eriRecord(contents []string) ([]*record.Record, error) {
+	account, err := p.account(contents)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get account for record: %v", err)
+	}
+	r := &record.Record{
+		Broker:      *account,
+		Action:      record.ExcessReportableIncome,
+		Ticker:      contents[5],
+		Name:        contents[6],
+		Description: contents[13],
+	}
+	// fill up timestamp, which is the fund distribution date of the reporting period
+	r.Timestamp, err = time.Parse(timeFmt, contents[0])
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse timestamp: %v", err)
+	}
+	// fill up the number of shares held at the end of the reporting period
+	r.ShareCount, err = strconv.ParseFloat(contents[7], 64)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %v to share count as float: %v", contents[7], err)
+	}
+	// fill up the ERI per share
+	r.PricePerShare, err = strconv.ParseFloat(contents[8], 64)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %v to ERI per share as float: %v", contents[8], err)
+	}
+	// fillup currency
+	r.Currency = record.NewCurrency(contents[9])
+	r.ExchangeRate, err = db.GetForex(r.Timestamp, r.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get forex: %v", err)
+	}
+	r.Total = r.ShareCount * r.PricePerShare * r.ExchangeRate
+	return []*record.Record{r}, nil
+}
@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+)
+
+// marshal writes the records to a CSV in the format of the default parser
+func marshal(t *testing.T, records ...*record.Record) *bytes.Buffer {
+	t.Helper()
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if err := w.Write(records[0].Header()); err != nil {
+		t.Fatalf("cannot write header: %v", err)
+	}
+	for _, r := range records {
+		if err := w.Write(r.MarshalCSV()); err != nil {
+			t.Fatalf("cannot write record: %v", err)
+		}
+	}
+	w.Flush()
+	return &b
+}
+
+func TestDefaultAssetType(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	gilt := &record.Record{
+		Timestamp:     time.Date(2023, time.June, 1, 10, 0, 0, 0, time.UTC),
+		Broker:        record.Account{Name: "ACC", Currency: record.GBP},
+		Action:        record.Buy,
+		Ticker:        "TG25",
+		Name:          "Treasury 0.625% 2025",
+		ShareCount:    1000,
+		PricePerShare: 98,
+		Currency:      record.GBP,
+		ExchangeRate:  1,
+		Total:         980,
+		Multiplier:    0.01,
+		AssetType:     record.GILT_ASSET,
+	}
+	b := marshal(t, gilt)
+
+	// The parser only reads the asset type, it is set in the db when the record is enriched
+	p := NewDefault()
+	rows, err := csv.NewReader(bytes.NewReader(b.Bytes())).ReadAll()
+	if err != nil {
+		t.Fatalf("cannot read CSV: %v", err)
+	}
+	got, err := p.ToRecord(rows[1])
+	if err != nil {
+		t.Fatalf("ToRecord() failed: %v", err)
+	}
+	if got[0].AssetType != record.GILT_ASSET {
+		t.Errorf("ToRecord() got asset type %q, want %q", got[0].AssetType, record.GILT_ASSET)
+	}
+	if _, err := db.TickerMeta("TG25"); err == nil {
+		t.Errorf("ToRecord() added TG25 to the db")
+	}
+
+	records, err := Parse(b, p)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if len(records) != 1 || records[0].AssetType != record.GILT_ASSET {
+		t.Fatalf("Parse() got %v, want 1 record of a gilt", records)
+	}
+	meta, err := db.TickerMeta("TG25")
+	if err != nil {
+		t.Fatalf("TickerMeta() failed: %v", err)
+	}
+	if meta.AssetType != record.GILT_ASSET {
+		t.Errorf("got asset type %q in the db, want %q", meta.AssetType, record.GILT_ASSET)
+	}
+}
+
+func TestDefaultAssetTypeHeader(t *testing.T) {
+	header := (&record.Record{}).Header()
+	header[19] = "Notes"
+	if err := NewDefault().ValidateHeader(header); err == nil {
+		t.Errorf("ValidateHeader(%v) succeeded, want an error", header)
+	}
+}
+
+func TestDefaultOptionalColumns(t *testing.T) {
+	header := (&record.Record{}).Header()
+	for _, tc := range []struct {
+		name    string
+		header  []string
+		wantErr bool
+	}{
+		{name: "required columns only", header: header[:14]},
+		{name: "owner only", header: header[:15]},
+		{name: "all columns", header: header},
+		{name: "unknown column", header: append(append([]string{}, header[:14]...), "Notes"), wantErr: true},
+		{name: "columns out of order", header: append(append([]string{}, header[:14]...), "Multiplier"), wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewDefault().ValidateHeader(tc.header)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("ValidateHeader(%v) got error %v, want error %t", tc.header, err, tc.wantErr)
+			}
+		})
+	}
+}
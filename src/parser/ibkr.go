@@ -10,10 +10,14 @@ import (
 	"aagr.xyz/trades/db"
 	"aagr.xyz/trades/record"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
 )
 
 type ibkrParser struct {
 	broker record.Account
+	// derivatives is true if the flex query has the contract details, needed for the trades of
+	// options and futures
+	derivatives bool
 }
 
 func NewIBKR(act record.Account) (*ibkrParser, error) {
@@ -38,18 +42,52 @@ func (p *ibkrParser) ValidateHeader(contents []string) error {
 		10: "NetCash",
 		11: "AssetClass",
 	}
-	return headerMatches(want, contents)
+	if err := headerMatches(want, contents); err != nil {
+		return err
+	}
+	// Older flex queries do not have the contract details, in which case options and futures are ignored
+	if len(contents) > 12 {
+		if err := headerMatches(map[int]string{
+			12: "Multiplier",
+			13: "Strike",
+			14: "Expiry",
+			15: "Put/Call",
+			16: "UnderlyingSymbol",
+			17: "Notes/Codes",
+		}, contents); err != nil {
+			return err
+		}
+		p.derivatives = true
+	}
+	return nil
 }
 
 func (p *ibkrParser) ToRecord(contents []string) ([]*record.Record, error) {
 	if contents[11] == "CASH" {
 		return p.cashCurrencyRecord(contents)
 	}
+	if contents[11] == "OPT" || contents[11] == "FUT" {
+		return p.derivativeRecord(contents)
+	}
 	if contents[11] != "STK" {
 		log.Warningf("invalid asset class passed %v, ignored", contents)
 		return nil, nil
 	}
+	r, err := p.tradeRecord(contents)
+	if err != nil {
+		return nil, err
+	}
+	var res []*record.Record
+	res = append(res, r)
+	if r.Currency != record.GBP {
+		res = append(res, p.forexRecord(r))
+	}
 
+	return res, nil
+}
+
+// tradeRecord returns the BUY or SELL of a trade
+func (p *ibkrParser) tradeRecord(contents []string) (*record.Record, error) {
 	r := &record.Record{Broker: p.broker}
 	var err error
 	// fill up timestamp
@@ -118,13 +156,95 @@ func (p *ibkrParser) ToRecord(contents []string) ([]*record.Record, error) {
 		return nil, fmt.Errorf("cannot convert %v to total as GBP as float: %v", contents[10], err)
 	}
 	r.Total = r.ExchangeRate * math.Abs(r.Total)
-	var res []*record.Record
-	res = append(res, r)
-	if r.Currency != record.GBP {
-		res = append(res, p.forexRecord(r))
+	return r, nil
+}
+
+// derivativeRecord returns the trade of an option or a future contract. An option closed by exercise or
+// assignment is an EXERCISE instead, as the trade of the underlying is a separate record.
+func (p *ibkrParser) derivativeRecord(contents []string) ([]*record.Record, error) {
+	if !p.derivatives {
+		log.Warningf("contract details of %s are not in the flex query, add them to include it: %v", contents[11], contents)
+		return nil, nil
+	}
+	assetType := record.OPTION_ASSET
+	if contents[11] == "FUT" {
+		assetType = record.FUTURE_ASSET
+	}
+	multiplier, err := strconv.ParseFloat(contents[12], 64)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %v to multiplier as float: %v", contents[12], err)
+	}
+	expiry, err := time.Parse("20060102", contents[14])
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse expiry: %v", err)
+	}
+	ticker := strings.Join(strings.Fields(contents[1]), " ")
+	name := fmt.Sprintf("%s %s %s", contents[16], assetType, expiry.Format("2006-01-02"))
+	if assetType == record.OPTION_ASSET {
+		name = fmt.Sprintf("%s %s %s", name, contents[13], contents[15])
 	}
 
-	return res, nil
+	codes := strings.Split(contents[17], ";")
+	if assetType == record.OPTION_ASSET && (slices.Contains(codes, "A") || slices.Contains(codes, "Ex")) {
+		if contents[15] != "C" && contents[15] != "P" {
+			return nil, fmt.Errorf("invalid option right %q: %v", contents[15], contents)
+		}
+		d := &record.ExerciseDetails{
+			Underlying: contents[16],
+			Call:       contents[15] == "C",
+			Assigned:   slices.Contains(codes, "A"),
+		}
+		r := &record.Record{
+			Broker:      p.broker,
+			Action:      record.Exercise,
+			Ticker:      ticker,
+			Name:        name,
+			Currency:    record.NewCurrency(contents[5]),
+			Multiplier:  multiplier,
+			Description: d.String(),
+			AssetType:   assetType,
+		}
+		r.Timestamp, err = time.Parse(timeFmt, contents[0])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse timestamp: %v", err)
+		}
+		r.ShareCount, err = strconv.ParseFloat(contents[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %v to contracts as float: %v", contents[3], err)
+		}
+		r.ShareCount = math.Abs(r.ShareCount)
+		r.ExchangeRate, err = strconv.ParseFloat(contents[6], 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %v to exchange rate as float: %v", contents[6], err)
+		}
+		return []*record.Record{r}, nil
+	}
+
+	r, err := p.tradeRecord(contents)
+	if err != nil {
+		return nil, err
+	}
+	r.Ticker = ticker
+	r.Name = name
+	r.Multiplier = multiplier
+	r.AssetType = assetType
+	if assetType == record.OPTION_ASSET {
+		res := []*record.Record{r}
+		// nothing is paid for an option expiring worthless
+		if r.Currency != record.GBP && r.Total > 0.0 {
+			res = append(res, p.forexRecord(r))
+		}
+		return res, nil
+	}
+	// Only the variation margin of a future is settled in cash, so the total is the notional value
+	// of the contracts, whose change is the profit
+	r.Total = r.ShareCount * r.PricePerShare * r.Multiplier * r.ExchangeRate
+	if r.Action == record.Buy {
+		r.Total += r.Commission
+	} else {
+		r.Total -= r.Commission
+	}
+	return []*record.Record{r}, nil
 }
 
 func (p *ibkrParser) forexRecord(trade *record.Record) *record.Record {
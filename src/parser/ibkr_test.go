@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"testing"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+)
+
+var ibkrHeader = []string{
+	"DateTime", "Symbol", "Buy/Sell", "Quantity", "TradePrice", "CurrencyPrimary", "FXRateToBase", "Taxes",
+	"IBCommission", "IBCommissionCurrency", "NetCash", "AssetClass",
+	"Multiplier", "Strike", "Expiry", "Put/Call", "UnderlyingSymbol", "Notes/Codes",
+}
+
+// ibkrOption is the symbol of an option in the flex query, whose spaces are collapsed in the ticker
+const ibkrOption = "ABC   230721C00100000"
+
+// ibkrContract returns the trade of a contract on ABC in USD at 0.8 GBP
+func ibkrContract(symbol, class, action, quantity, price, commission, netCash, right, codes string) []string {
+	return []string{
+		"2023-07-21 10:00:00", symbol, action, quantity, price, "USD", "0.8", "0",
+		commission, "USD", netCash, class, "100", "100", "20230721", right, "ABC", codes,
+	}
+}
+
+func TestIBKRDerivatives(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	p, err := NewIBKR(record.Account{Name: "IBKR", Currency: record.MULTIPLE})
+	if err != nil {
+		t.Fatalf("NewIBKR() failed: %v", err)
+	}
+	if err := p.ValidateHeader(ibkrHeader); err != nil {
+		t.Fatalf("ValidateHeader() failed: %v", err)
+	}
+	for _, tc := range []struct {
+		name     string
+		contents []string
+		want     []*record.Record
+	}{
+		{
+			// The premium is paid in USD, which is sold
+			name:     "buy option",
+			contents: ibkrContract(ibkrOption, "OPT", "BUY", "2", "5", "-2", "-1002", "C", "O"),
+			want: []*record.Record{
+				{Action: record.Buy, Ticker: "ABC 230721C00100000", ShareCount: 2, Total: 801.6, Commission: 1.6},
+				{Action: record.Sell, Ticker: "USD", ShareCount: 1002, Total: 801.6},
+			},
+		},
+		{
+			// Nothing is paid for an option expiring worthless
+			name:     "expiry",
+			contents: ibkrContract(ibkrOption, "OPT", "SELL", "-2", "0", "0", "0", "C", "Ep"),
+			want: []*record.Record{
+				{Action: record.Sell, Ticker: "ABC 230721C00100000", ShareCount: 2, Total: 0},
+			},
+		},
+		{
+			// The total of a future is the notional value of the contracts
+			name:     "buy future",
+			contents: ibkrContract("ESU3", "FUT", "BUY", "1", "40", "-2", "-2", "", "O"),
+			want: []*record.Record{
+				{Action: record.Buy, Ticker: "ESU3", ShareCount: 1, Total: 3201.6, Commission: 1.6},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := p.ToRecord(tc.contents)
+			if err != nil {
+				t.Fatalf("ToRecord() failed: %v", err)
+			}
+			checkTrades(t, got, tc.want)
+			for _, r := range got {
+				if r.Ticker == string(record.USD) {
+					continue
+				}
+				if r.Multiplier != 100 {
+					t.Errorf("got multiplier %f, want 100", r.Multiplier)
+				}
+			}
+		})
+	}
+}
+
+func TestIBKRExercise(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	p, err := NewIBKR(record.Account{Name: "IBKR", Currency: record.MULTIPLE})
+	if err != nil {
+		t.Fatalf("NewIBKR() failed: %v", err)
+	}
+	if err := p.ValidateHeader(ibkrHeader); err != nil {
+		t.Fatalf("ValidateHeader() failed: %v", err)
+	}
+	for _, tc := range []struct {
+		name     string
+		contents []string
+		wantDesc string
+	}{
+		{name: "exercised", contents: ibkrContract(ibkrOption, "OPT", "SELL", "-1", "0", "0", "0", "C", "Ex"), wantDesc: "ABC CALL EXERCISED"},
+		{name: "assigned", contents: ibkrContract(ibkrOption, "OPT", "BUY", "1", "0", "0", "0", "P", "A"), wantDesc: "ABC PUT ASSIGNED"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := p.ToRecord(tc.contents)
+			if err != nil {
+				t.Fatalf("ToRecord() failed: %v", err)
+			}
+			// The trade of the underlying is a separate record
+			if len(got) != 1 {
+				t.Fatalf("got %d records, want 1: %v", len(got), got)
+			}
+			if r := got[0]; r.Action != record.Exercise || r.Ticker != "ABC 230721C00100000" || r.ShareCount != 1 || r.Description != tc.wantDesc {
+				t.Errorf("got %v with description %q, want %s of 1 contract with description %q", r, r.Description, record.Exercise, tc.wantDesc)
+			}
+		})
+	}
+}
+
+func TestIBKRWithoutContractDetails(t *testing.T) {
+	p, err := NewIBKR(record.Account{Name: "IBKR", Currency: record.MULTIPLE})
+	if err != nil {
+		t.Fatalf("NewIBKR() failed: %v", err)
+	}
+	if err := p.ValidateHeader(ibkrHeader[:12]); err != nil {
+		t.Fatalf("ValidateHeader() failed: %v", err)
+	}
+	got, err := p.ToRecord(ibkrContract(ibkrOption, "OPT", "BUY", "2", "5", "-2", "-1002", "C", "O")[:12])
+	if err != nil || len(got) != 0 {
+		t.Errorf("ToRecord() = %v, %v, want the option skipped", got, err)
+	}
+}
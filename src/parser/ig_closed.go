@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"aagr.xyz/trades/record"
+	log "github.com/sirupsen/logrus"
+)
+
+// igClosedParser parses the closed positions in the transaction history of an IG spread betting
+// or CFD account. Every position is a single record of its profit or loss.
+type igClosedParser struct {
+	act        record.Account
+	instrument record.AssetType
+}
+
+// NewIGSpreadBet returns a parser for the transaction history of an IG spread betting account,
+// whose profits are exempt from CGT.
+func NewIGSpreadBet(act record.Account) (*igClosedParser, error) {
+	if act.Currency != record.GBP {
+		return nil, fmt.Errorf("IG spread bet parser works with GBP currency, got %s", act.Currency)
+	}
+	if !act.CGTExempt {
+		return nil, fmt.Errorf("spread betting account %s must be CGT exempt", act.Name)
+	}
+	return &igClosedParser{act: act, instrument: record.SPREAD_BET_ASSET}, nil
+}
+
+// NewIGCFD returns a parser for the transaction history of an IG CFD account.
+func NewIGCFD(act record.Account) (*igClosedParser, error) {
+	if act.Currency != record.GBP {
+		return nil, fmt.Errorf("IG CFD parser works with GBP currency, got %s", act.Currency)
+	}
+	return &igClosedParser{act: act, instrument: record.CFD_ASSET}, nil
+}
+
+func (p *igClosedParser) ValidateHeader(contents []string) error {
+	want := map[int]string{
+		2:  "MarketName",
+		5:  "Transaction type",
+		8:  "Close level",
+		9:  "Size",
+		11: "PL Amount",
+		13: "DateUtc",
+		14: "OpenDateUtc",
+	}
+	return headerMatches(want, contents)
+}
+
+func (p *igClosedParser) ToRecord(contents []string) ([]*record.Record, error) {
+	if contents[5] != "DEAL" {
+		log.Warningf("IG transaction type %q is not a closed position, add it manually: %v", contents[5], contents)
+		return nil, nil
+	}
+	r := &record.Record{
+		Broker:       p.act,
+		Action:       record.ClosedPosition,
+		Ticker:       strings.TrimSpace(contents[2]),
+		Name:         strings.TrimSpace(contents[2]),
+		Currency:     record.GBP,
+		ExchangeRate: 1.0,
+		AssetType:    p.instrument,
+	}
+	var err error
+	r.Timestamp, err = time.Parse(time.RFC3339, fmt.Sprintf("%sZ", contents[13]))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse timestamp: %v", err)
+	}
+	opened, err := time.Parse(time.RFC3339, fmt.Sprintf("%sZ", contents[14]))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse opening timestamp: %v", err)
+	}
+	r.Description = (&record.ClosedPositionDetails{Instrument: p.instrument, Opened: opened}).String()
+	// the size is negative for a short position
+	r.ShareCount, err = strconv.ParseFloat(strings.ReplaceAll(contents[9], ",", ""), 64)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %v to size as float: %v", contents[9], err)
+	}
+	r.ShareCount = math.Abs(r.ShareCount)
+	r.PricePerShare, err = strconv.ParseFloat(strings.ReplaceAll(contents[8], ",", ""), 64)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %v to close level as float: %v", contents[8], err)
+	}
+	r.Total, err = strconv.ParseFloat(strings.ReplaceAll(contents[11], ",", ""), 64)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %v to profit as float: %v", contents[11], err)
+	}
+	return []*record.Record{r}, nil
+}
@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+)
+
+func TestIGClosed(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	header := []string{
+		"Date", "Reference", "MarketName", "Period", "Opening", "Transaction type", "Open level", "Currency",
+		"Close level", "Size", "Cash transaction", "PL Amount", "Date", "DateUtc", "OpenDateUtc",
+	}
+	// row returns a closed position of the given size, which is negative for a short one, and profit
+	row := func(size, profit string) []string {
+		return []string{
+			"01/06/2023", "REF1", "FTSE 100", "DFB", "7,500.0", "DEAL", "7,500.0", "GBP", "7,600.0", size, "", profit,
+			"01/06/2023", "2023-06-01T10:00:00", "2023-05-02T09:00:00",
+		}
+	}
+	spreadBet, err := NewIGSpreadBet(record.Account{Name: "IG-SB", Currency: record.GBP, CGTExempt: true})
+	if err != nil {
+		t.Fatalf("NewIGSpreadBet() failed: %v", err)
+	}
+	cfd, err := NewIGCFD(record.Account{Name: "IG-CFD", Currency: record.GBP})
+	if err != nil {
+		t.Fatalf("NewIGCFD() failed: %v", err)
+	}
+	for _, tc := range []struct {
+		name     string
+		p        *igClosedParser
+		contents []string
+		// want are the size and the profit of the position
+		wantSize, wantProfit float64
+		wantInstrument       record.AssetType
+	}{
+		{name: "long", p: spreadBet, contents: row("2", "200.00"), wantSize: 2, wantProfit: 200, wantInstrument: record.SPREAD_BET_ASSET},
+		{name: "short", p: spreadBet, contents: row("-1.5", "-150.00"), wantSize: 1.5, wantProfit: -150, wantInstrument: record.SPREAD_BET_ASSET},
+		{name: "loss", p: cfd, contents: row("-1,000", "-1,234.50"), wantSize: 1000, wantProfit: -1234.5, wantInstrument: record.CFD_ASSET},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.p.ValidateHeader(header); err != nil {
+				t.Fatalf("ValidateHeader() failed: %v", err)
+			}
+			got, err := tc.p.ToRecord(tc.contents)
+			if err != nil {
+				t.Fatalf("ToRecord() failed: %v", err)
+			}
+			if len(got) != 1 {
+				t.Fatalf("got %d records, want 1: %v", len(got), got)
+			}
+			r := got[0]
+			if r.Action != record.ClosedPosition || r.Ticker != "FTSE 100" || !near(r.ShareCount, tc.wantSize) || !near(r.Total, tc.wantProfit) {
+				t.Errorf("got %v, want %s of size %f with profit %.2f", r, record.ClosedPosition, tc.wantSize, tc.wantProfit)
+			}
+			d, err := record.ParseClosedPosition(r.Description)
+			if err != nil {
+				t.Fatalf("ParseClosedPosition(%q) failed: %v", r.Description, err)
+			}
+			if d.Instrument != tc.wantInstrument || !d.Opened.Equal(time.Date(2023, time.May, 2, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("got %v, want %s opened on 2023-05-02", d, tc.wantInstrument)
+			}
+		})
+	}
+}
+
+func TestIGSpreadBetNotExempt(t *testing.T) {
+	if _, err := NewIGSpreadBet(record.Account{Name: "IG-SB", Currency: record.GBP}); err == nil {
+		t.Errorf("NewIGSpreadBet() of an account not exempt from CGT succeeded, want an error")
+	}
+}
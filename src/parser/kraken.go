@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+)
+
+// krakenQuotes are the assets a Kraken pair can be quoted in, longest first so that the suffix
+// of the pair is matched unambiguously
+var krakenQuotes = []string{
+	"USDT", "USDC", "ZGBP", "ZUSD", "ZEUR", "ZCHF", "XXBT", "XETH",
+	"GBP", "USD", "EUR", "CHF", "XBT", "ETH",
+}
+
+// krakenRenames are the Kraken asset codes which differ from the usual ticker
+var krakenRenames = map[string]string{
+	"XBT": "BTC",
+	"XDG": "DOGE",
+}
+
+type krakenParser struct {
+	broker record.Account
+}
+
+// NewKraken returns a parser for the trades CSV export of Kraken
+func NewKraken(act record.Account) (*krakenParser, error) {
+	if act.Currency != record.GBP {
+		return nil, fmt.Errorf("Kraken parser works with GBP currency, got %s", act.Currency)
+	}
+	return &krakenParser{broker: act}, nil
+}
+
+func (p *krakenParser) ValidateHeader(contents []string) error {
+	want := map[int]string{
+		2: "pair",
+		3: "time",
+		4: "type",
+		6: "price",
+		7: "cost",
+		8: "fee",
+		9: "vol",
+	}
+	// The GBP price of the quote asset is an optional column added after the export, which is
+	// needed for the crypto to crypto trades
+	if len(contents) > 13 {
+		want[13] = "quote gbp price"
+	}
+	return headerMatches(want, contents)
+}
+
+// krakenAsset returns the ticker of a Kraken asset code, like BTC for XXBT
+func krakenAsset(code string) string {
+	if len(code) == 4 && (code[0] == 'X' || code[0] == 'Z') {
+		code = code[1:]
+	}
+	if ticker, ok := krakenRenames[code]; ok {
+		return ticker
+	}
+	return code
+}
+
+// krakenPair splits a Kraken pair like XETHXXBT into the base and the quote asset
+func krakenPair(pair string) (string, string, error) {
+	for _, quote := range krakenQuotes {
+		if base, found := strings.CutSuffix(pair, quote); found && base != "" {
+			return krakenAsset(base), krakenAsset(quote), nil
+		}
+	}
+	return "", "", fmt.Errorf("cannot find the quote asset of pair %s", pair)
+}
+
+func (p *krakenParser) ToRecord(contents []string) ([]*record.Record, error) {
+	ts, err := time.Parse(timeFmt, contents[3])
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse timestamp: %v", err)
+	}
+	base, quote, err := krakenPair(strings.ToUpper(contents[2]))
+	if err != nil {
+		return nil, err
+	}
+	var cost, fee, vol float64
+	for idx, val := range map[int]*float64{7: &cost, 8: &fee, 9: &vol} {
+		*val, err = strconv.ParseFloat(contents[idx], 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %v to float: %v", contents[idx], err)
+		}
+	}
+	action := record.NewTransactionType(contents[4])
+	if action != record.Buy && action != record.Sell {
+		return nil, fmt.Errorf("invalid action type %s", contents[4])
+	}
+	if curr := record.NewCurrency(quote); curr != "" {
+		if curr != record.GBP {
+			return nil, fmt.Errorf("Kraken parser works with GBP or crypto to crypto pairs, got %s", contents[2])
+		}
+		return []*record.Record{cryptoRecord(p.broker, ts, action, base, vol, cost, fee)}, nil
+	}
+	// A crypto to crypto trade is valued in GBP with the price of the quote asset, in which
+	// the cost and the fee are. There is no market data for the past, so it has to be in the input.
+	if len(contents) <= 13 || contents[13] == "" {
+		return nil, fmt.Errorf("GBP price of %s is needed in the quote gbp price column for a %s trade: %v", quote, contents[2], contents)
+	}
+	price, err := strconv.ParseFloat(contents[13], 64)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %v to GBP price of %s as float: %v", contents[13], quote, err)
+	}
+	if price <= 0.0 {
+		return nil, fmt.Errorf("invalid GBP price %f of %s: %v", price, quote, contents)
+	}
+	db.AddPrice(ts, quote, price)
+	if action == record.Buy {
+		return cryptoExchange(p.broker, ts, quote, cost+fee, base, vol, (cost+fee)*price, fee*price), nil
+	}
+	return cryptoExchange(p.broker, ts, base, vol, quote, cost-fee, cost*price, fee*price), nil
+}
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"testing"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+)
+
+func TestKraken(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	p, err := NewKraken(record.Account{Name: "KRAKEN", Currency: record.GBP})
+	if err != nil {
+		t.Fatalf("NewKraken() failed: %v", err)
+	}
+	header := []string{"txid", "ordertxid", "pair", "time", "type", "ordertype", "price", "cost", "fee", "vol", "margin", "misc", "ledgers", "quote gbp price"}
+	if err := p.ValidateHeader(header); err != nil {
+		t.Fatalf("ValidateHeader() failed: %v", err)
+	}
+	for _, tc := range []struct {
+		name     string
+		contents []string
+		want     []*record.Record
+	}{
+		{
+			name:     "buy for GBP",
+			contents: []string{"T1", "O1", "XXBTZGBP", "2023-06-01 10:00:00", "buy", "limit", "30000", "300", "1.2", "0.01", "0", "", "", ""},
+			want: []*record.Record{
+				{Action: record.Buy, Ticker: "BTC", ShareCount: 0.01, Total: 301.2, Commission: 1.2},
+			},
+		},
+		{
+			name:     "sell for GBP",
+			contents: []string{"T2", "O2", "XXBTZGBP", "2023-06-01 10:00:00", "sell", "limit", "30000", "300", "1.2", "0.01", "0", "", "", ""},
+			want: []*record.Record{
+				{Action: record.Sell, Ticker: "BTC", ShareCount: 0.01, Total: 298.8, Commission: 1.2},
+			},
+		},
+		{
+			// The BTC paid, including the fee, is disposed of
+			name:     "buy for crypto",
+			contents: []string{"T3", "O3", "XETHXXBT", "2023-06-01 10:00:00", "buy", "limit", "0.05", "0.05", "0.0001", "1", "0", "", "", "30000"},
+			want: []*record.Record{
+				{Action: record.Sell, Ticker: "BTC", ShareCount: 0.0501, Total: 1500, Commission: 3},
+				{Action: record.Buy, Ticker: "ETH", ShareCount: 1, Total: 1500},
+			},
+		},
+		{
+			// The BTC received is net of the fee
+			name:     "sell for crypto",
+			contents: []string{"T4", "O4", "XETHXXBT", "2023-06-01 10:00:00", "sell", "limit", "0.05", "0.05", "0.0001", "1", "0", "", "", "30000"},
+			want: []*record.Record{
+				{Action: record.Sell, Ticker: "ETH", ShareCount: 1, Total: 1497, Commission: 3},
+				{Action: record.Buy, Ticker: "BTC", ShareCount: 0.0499, Total: 1497},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := p.ToRecord(tc.contents)
+			if err != nil {
+				t.Fatalf("ToRecord() failed: %v", err)
+			}
+			checkTrades(t, got, tc.want)
+		})
+	}
+}
+
+func TestKrakenWithoutQuotePrice(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	p, err := NewKraken(record.Account{Name: "KRAKEN", Currency: record.GBP})
+	if err != nil {
+		t.Fatalf("NewKraken() failed: %v", err)
+	}
+	// A crypto to crypto trade cannot be valued without the GBP price of the quote asset
+	for _, contents := range [][]string{
+		{"T1", "O1", "XETHXXBT", "2023-06-01 10:00:00", "buy", "limit", "0.05", "0.05", "0.0001", "1", "0", "", ""},
+		{"T1", "O1", "XETHXXBT", "2023-06-01 10:00:00", "buy", "limit", "0.05", "0.05", "0.0001", "1", "0", "", "", ""},
+	} {
+		if _, err := p.ToRecord(contents); err == nil {
+			t.Errorf("ToRecord(%v) succeeded, want an error", contents)
+		}
+	}
+}
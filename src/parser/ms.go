@@ -14,6 +14,9 @@ import (
 
 type msVestParser struct {
 	broker record.Account
+	// withheld is true if the release report has the net shares, so that the shares withheld for
+	// tax can be worked out
+	withheld bool
 }
 
 func NewMSVest(act record.Account) (*msVestParser, error) {
@@ -31,40 +34,80 @@ func (p *msVestParser) ValidateHeader(contents []string) error {
 		5: "Price",
 		6: "Quantity",
 	}
-	return headerMatches(want, contents)
+	if err := headerMatches(want, contents); err != nil {
+		return err
+	}
+	// Older reports do not have the net shares, in which case nothing is assumed to be withheld
+	if len(contents) > 8 {
+		if err := headerMatches(map[int]string{8: "Net Share Proceeds"}, contents); err != nil {
+			return err
+		}
+		p.withheld = true
+	}
+	return nil
 }
 
+// ToRecord returns the employment income of the vest, along with a BUY of the shares kept at the
+// market value on vest, which is their base cost. The shares withheld for tax are never received.
 func (p *msVestParser) ToRecord(contents []string) ([]*record.Record, error) {
-	r := &record.Record{
-		Broker:     p.broker,
-		Action:     record.NewTransactionType("buy"),
-		Currency:   record.USD,
-		Ticker:     "GOOG",
-		Commission: 0.0,
+	income := &record.Record{
+		Broker:   p.broker,
+		Action:   record.ShareSchemeIncome,
+		Currency: record.USD,
+		Ticker:   "GOOG",
 	}
 	var err error
-	r.Timestamp, err = time.Parse("02-Jan-2006", contents[0])
+	income.Timestamp, err = time.Parse("02-Jan-2006", contents[0])
 	if err != nil {
 		return nil, fmt.Errorf("cannot parse date %s: %v", contents[0], err)
 	}
 	if contents[2] != "GSU Class C" {
 		return nil, fmt.Errorf("invalid share class passed %v", contents[2])
 	}
-	r.ShareCount, err = strconv.ParseFloat(contents[6], 64)
+	income.ShareCount, err = strconv.ParseFloat(contents[6], 64)
 	if err != nil {
 		return nil, fmt.Errorf("cannot get share count: %v", err)
 	}
 	price := strings.ReplaceAll(strings.ReplaceAll(contents[5], "$", ""), ",", "")
-	r.PricePerShare, err = strconv.ParseFloat(price, 64)
+	income.PricePerShare, err = strconv.ParseFloat(price, 64)
 	if err != nil {
 		return nil, fmt.Errorf("cannot parse price %s: %v", price, err)
 	}
-	r.ExchangeRate, err = db.GetForex(r.Timestamp, r.Currency)
+	income.ExchangeRate, err = db.GetForex(income.Timestamp, income.Currency)
 	if err != nil {
 		return nil, fmt.Errorf("cannot get forex: %v", err)
 	}
+	income.Total = income.PricePerShare * income.ShareCount * income.ExchangeRate
+
+	scheme := &record.ShareSchemeDetails{Scheme: "RSU"}
+	if p.withheld && len(contents) > 8 && contents[8] != "" {
+		net, err := strconv.ParseFloat(strings.ReplaceAll(contents[8], ",", ""), 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get net share count: %v", err)
+		}
+		if net < 0.0 || net > income.ShareCount {
+			return nil, fmt.Errorf("invalid net share count %f of %f vested shares", net, income.ShareCount)
+		}
+		scheme.Withheld = income.ShareCount - net
+	}
+	income.Description = scheme.String()
+
+	r := &record.Record{
+		Timestamp:     income.Timestamp,
+		Broker:        p.broker,
+		Action:        record.Buy,
+		Currency:      record.USD,
+		Ticker:        "GOOG",
+		ShareCount:    income.ShareCount - scheme.Withheld,
+		PricePerShare: income.PricePerShare,
+		ExchangeRate:  income.ExchangeRate,
+		Commission:    0.0,
+	}
+	if r.ShareCount <= 0.0 {
+		return []*record.Record{income}, nil
+	}
 	r.Total = r.PricePerShare * r.ShareCount * r.ExchangeRate
-	return []*record.Record{r, p.cashInRecord(r)}, nil
+	return []*record.Record{income, r, p.cashInRecord(r)}, nil
 }
 
 func (p *msVestParser) cashInRecord(vest *record.Record) *record.Record {
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"aagr.xyz/trades/db"
+	"aagr.xyz/trades/record"
+)
+
+func TestMSVest(t *testing.T) {
+	db.InitDB(t.TempDir(), nil)
+	db.AddForex(time.Date(2023, time.June, 26, 0, 0, 0, 0, time.UTC), record.USD, 0.8)
+	header := []string{"Date", "Order Number", "Plan", "Type", "Status", "Price", "Quantity", "Net Cash Proceeds"}
+	row := []string{"26-Jun-2023", "N/A", "GSU Class C", "Release", "Complete", "$120.00", "10", "$0.00"}
+	for _, tc := range []struct {
+		name string
+		// net is the column of the net shares, if any
+		net          []string
+		wantWithheld float64
+		// wantErr is set if the header is invalid
+		wantErr bool
+	}{
+		// Older release reports do not have the net shares, so nothing is withheld
+		{name: "without net shares"},
+		{name: "with net shares", net: []string{"Net Share Proceeds", "6"}, wantWithheld: 4},
+		{name: "unknown column", net: []string{"Notes", "6"}, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewMSVest(record.Account{Name: "MS", Currency: record.USD})
+			if err != nil {
+				t.Fatalf("NewMSVest() failed: %v", err)
+			}
+			contents := append([]string{}, row...)
+			h := append([]string{}, header...)
+			if tc.net != nil {
+				h = append(h, tc.net[0])
+				contents = append(contents, tc.net[1])
+			}
+			if err := p.ValidateHeader(h); err != nil || tc.wantErr {
+				if !tc.wantErr {
+					t.Fatalf("ValidateHeader() failed: %v", err)
+				}
+				if err == nil {
+					t.Fatalf("ValidateHeader() succeeded, want an error")
+				}
+				return
+			}
+			records, err := p.ToRecord(contents)
+			if err != nil {
+				t.Fatalf("ToRecord() failed: %v", err)
+			}
+			if len(records) != 3 {
+				t.Fatalf("got %d records, want the income, the BUY and the CASHIN: %v", len(records), records)
+			}
+			income, buy := records[0], records[1]
+			// The whole vest is employment income
+			if income.Action != record.ShareSchemeIncome || !near(income.Total, 10*120*0.8) {
+				t.Errorf("got income %v, want %s of total %.2f", income, record.ShareSchemeIncome, 10*120*0.8)
+			}
+			d, err := record.ParseShareScheme(income.Description)
+			if err != nil {
+				t.Fatalf("ParseShareScheme(%q) failed: %v", income.Description, err)
+			}
+			if !near(d.Withheld, tc.wantWithheld) {
+				t.Errorf("got %f shares withheld, want %f", d.Withheld, tc.wantWithheld)
+			}
+			// Only the shares kept are acquired
+			kept := 10 - tc.wantWithheld
+			if buy.Action != record.Buy || !near(buy.ShareCount, kept) || !near(buy.Total, kept*120*0.8) {
+				t.Errorf("got %v, want %s of %f shares for %.2f", buy, record.Buy, kept, kept*120*0.8)
+			}
+		})
+	}
+}
@@ -66,6 +66,31 @@ func Parse(in io.Reader, parser Parser) ([]*record.Record, error) {
 	return res, nil
 }
 
+// enrichDemerger adds the new ticker to the db, and fills in the share prices of both the tickers
+// on the day of the demerger from the db, if they are not in the description.
+func enrichDemerger(r *record.Record) error {
+	d, err := record.ParseDemerger(r.Description)
+	if err != nil {
+		return err
+	}
+	if _, err := db.TickerName(d.NewTicker); err != nil {
+		return fmt.Errorf("cannot add new ticker to db: %v", err)
+	}
+	if d.HasPrices() {
+		db.AddPrice(r.Timestamp, r.Ticker, d.OldSharePrice)
+		db.AddPrice(r.Timestamp, d.NewTicker, d.NewSharePrice)
+		return nil
+	}
+	if d.OldSharePrice, err = db.GetPrice(r.Timestamp, r.Ticker); err != nil {
+		return fmt.Errorf("cannot get price of %s: %v", r.Ticker, err)
+	}
+	if d.NewSharePrice, err = db.GetPrice(r.Timestamp, d.NewTicker); err != nil {
+		return fmt.Errorf("cannot get price of %s: %v", d.NewTicker, err)
+	}
+	r.Description = d.String()
+	return nil
+}
+
 func validateAndEnrich(r *record.Record) error {
 	// Let's store everything in GBP
 	if r.Currency == record.GBX {
@@ -73,14 +98,47 @@ func validateAndEnrich(r *record.Record) error {
 		r.Currency = record.GBP
 		r.ExchangeRate = 1.0
 	}
+	if err := db.FillTickerOrName(r); err != nil {
+		return fmt.Errorf("cannot fill ticker or name from db: %v", err)
+	}
+	if r.AssetType != record.UNKNOWN_ASSET {
+		db.SetAssetType(r.Ticker, r.AssetType)
+	}
+	// Bonds are priced per 100 of nominal, which is the quantity
+	if meta, err := db.TickerMeta(r.Ticker); err == nil && meta.AssetType.IsBond() && r.Multiplier == 0.0 {
+		switch r.Action {
+		case record.Buy, record.Sell:
+			r.Multiplier = 0.01
+		}
+	}
 	if err := r.AssertMaths(); err != nil {
 		return fmt.Errorf("cannot assert the maths for the record (record = %s): %v", r.String(), err)
 	}
-	if err := db.FillTickerOrName(r); err != nil {
-		return fmt.Errorf("cannot fill ticker or name from db: %v", err)
+	// The new ticker of a takeover may not have any other record, so make sure it is known
+	if r.Action == record.Takeover {
+		t, err := record.ParseTakeover(r.Description)
+		if err != nil {
+			return fmt.Errorf("cannot parse takeover: %v", err)
+		}
+		if _, err := db.TickerName(t.NewTicker); err != nil {
+			return fmt.Errorf("cannot add new ticker of takeover to db: %v", err)
+		}
+	}
+	if r.Action == record.Demerger {
+		if err := enrichDemerger(r); err != nil {
+			return fmt.Errorf("cannot enrich demerger: %v", err)
+		}
+	}
+	// The ticker is not quoted in the market any more, so stop asking for its price
+	if r.Action == record.NegligibleValue {
+		if err := db.SetState(r.Ticker, db.Inactive); err != nil {
+			return fmt.Errorf("cannot mark ticker %s as inactive: %v", r.Ticker, err)
+		}
 	}
-	// If it is not a buy or sell transaction, then don't fiddle around with currency
-	if !(r.Action == record.Sell || r.Action == record.Buy) {
+	// If it is not a transaction in shares of the ticker, then don't fiddle around with currency
+	switch r.Action {
+	case record.Sell, record.Buy, record.RightsIssue, record.ScripDividend, record.CashInLieu, record.ESPPPurchase:
+	default:
 		return nil
 	}
 	if err := db.SetCurrency(r.Ticker, r.Currency); err != nil {
@@ -0,0 +1,29 @@
+package parser
+
+import (
+	"math"
+	"testing"
+
+	"aagr.xyz/trades/record"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
+
+// checkTrades compares the records against the wanted ones, looking only at the fields of a trade
+// which go into the CGT calculation
+func checkTrades(t *testing.T, got, want []*record.Record) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d records, want %d: %v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Action != w.Action || g.Ticker != w.Ticker || !near(g.ShareCount, w.ShareCount) ||
+			!near(g.Total, w.Total) || !near(g.Commission, w.Commission) {
+			t.Errorf("record %d: got %v, want %s %f %s for total %.2f GBP and commission %.2f GBP",
+				i, g, w.Action, w.ShareCount, w.Ticker, w.Total, w.Commission)
+		}
+	}
+}
@@ -26,6 +26,7 @@ type Statements struct {
 	unknownFields protoimpl.UnknownFields
 
 	Statements []*Statement `protobuf:"bytes,1,rep,name=statements,proto3" json:"statements,omitempty"`
+	TaxYears   []*TaxYear   `protobuf:"bytes,2,rep,name=tax_years,json=taxYears,proto3" json:"tax_years,omitempty"`
 }
 
 func (x *Statements) Reset() {
@@ -67,6 +68,90 @@ func (x *Statements) GetStatements() []*Statement {
 	return nil
 }
 
+func (x *Statements) GetTaxYears() []*TaxYear {
+	if x != nil {
+		return x.TaxYears
+	}
+	return nil
+}
+
+// TaxYear stores the personal details needed to calculate the CGT liability of a tax year
+type TaxYear struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// name of the tax year, e.g. 2023-24
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// income after personal allowance, used to find out how much of the basic rate band is left
+	TaxableIncome float64 `protobuf:"fixed64,2,opt,name=taxable_income,json=taxableIncome,proto3" json:"taxable_income,omitempty"`
+	// whether the losses of this tax year have been reported to HMRC. Unclaimed losses
+	// cannot be used once four years have passed since the end of the tax year.
+	LossesClaimed bool `protobuf:"varint,3,opt,name=losses_claimed,json=lossesClaimed,proto3" json:"losses_claimed,omitempty"`
+	// owner the details belong to, matching Account.owner. Empty for a single person.
+	Owner string `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`
+}
+
+func (x *TaxYear) Reset() {
+	*x = TaxYear{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_statements_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaxYear) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaxYear) ProtoMessage() {}
+
+func (x *TaxYear) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_statements_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaxYear.ProtoReflect.Descriptor instead.
+func (*TaxYear) Descriptor() ([]byte, []int) {
+	return file_proto_statements_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TaxYear) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TaxYear) GetTaxableIncome() float64 {
+	if x != nil {
+		return x.TaxableIncome
+	}
+	return 0
+}
+
+func (x *TaxYear) GetLossesClaimed() bool {
+	if x != nil {
+		return x.LossesClaimed
+	}
+	return false
+}
+
+func (x *TaxYear) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
 type Statement struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -82,6 +167,10 @@ type Statement struct {
 	//	*Statement_IgDividendParser
 	//	*Statement_MsVestParser
 	//	*Statement_MsWithdrawlParser
+	//	*Statement_CoinbaseParser
+	//	*Statement_KrakenParser
+	//	*Statement_IgSpreadBetParser
+	//	*Statement_IgCfdParser
 	ParserOneof isStatement_ParserOneof `protobuf_oneof:"parser_oneof"`
 	Directory   string                  `protobuf:"bytes,100,opt,name=directory,proto3" json:"directory,omitempty"`
 	Filenames   []string                `protobuf:"bytes,101,rep,name=filenames,proto3" json:"filenames,omitempty"`
@@ -90,7 +179,7 @@ type Statement struct {
 func (x *Statement) Reset() {
 	*x = Statement{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_statements_proto_msgTypes[1]
+		mi := &file_proto_statements_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -103,7 +192,7 @@ func (x *Statement) String() string {
 func (*Statement) ProtoMessage() {}
 
 func (x *Statement) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_statements_proto_msgTypes[1]
+	mi := &file_proto_statements_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -116,7 +205,7 @@ func (x *Statement) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Statement.ProtoReflect.Descriptor instead.
 func (*Statement) Descriptor() ([]byte, []int) {
-	return file_proto_statements_proto_rawDescGZIP(), []int{1}
+	return file_proto_statements_proto_rawDescGZIP(), []int{2}
 }
 
 func (m *Statement) GetParserOneof() isStatement_ParserOneof {
@@ -182,6 +271,34 @@ func (x *Statement) GetMsWithdrawlParser() *MSWithdrawlParser {
 	return nil
 }
 
+func (x *Statement) GetCoinbaseParser() *CoinbaseParser {
+	if x, ok := x.GetParserOneof().(*Statement_CoinbaseParser); ok {
+		return x.CoinbaseParser
+	}
+	return nil
+}
+
+func (x *Statement) GetKrakenParser() *KrakenParser {
+	if x, ok := x.GetParserOneof().(*Statement_KrakenParser); ok {
+		return x.KrakenParser
+	}
+	return nil
+}
+
+func (x *Statement) GetIgSpreadBetParser() *IGSpreadBetParser {
+	if x, ok := x.GetParserOneof().(*Statement_IgSpreadBetParser); ok {
+		return x.IgSpreadBetParser
+	}
+	return nil
+}
+
+func (x *Statement) GetIgCfdParser() *IGCFDParser {
+	if x, ok := x.GetParserOneof().(*Statement_IgCfdParser); ok {
+		return x.IgCfdParser
+	}
+	return nil
+}
+
 func (x *Statement) GetDirectory() string {
 	if x != nil {
 		return x.Directory
@@ -232,6 +349,22 @@ type Statement_MsWithdrawlParser struct {
 	MsWithdrawlParser *MSWithdrawlParser `protobuf:"bytes,7,opt,name=ms_withdrawl_parser,json=msWithdrawlParser,proto3,oneof"`
 }
 
+type Statement_CoinbaseParser struct {
+	CoinbaseParser *CoinbaseParser `protobuf:"bytes,9,opt,name=coinbase_parser,json=coinbaseParser,proto3,oneof"`
+}
+
+type Statement_KrakenParser struct {
+	KrakenParser *KrakenParser `protobuf:"bytes,10,opt,name=kraken_parser,json=krakenParser,proto3,oneof"`
+}
+
+type Statement_IgSpreadBetParser struct {
+	IgSpreadBetParser *IGSpreadBetParser `protobuf:"bytes,11,opt,name=ig_spread_bet_parser,json=igSpreadBetParser,proto3,oneof"`
+}
+
+type Statement_IgCfdParser struct {
+	IgCfdParser *IGCFDParser `protobuf:"bytes,12,opt,name=ig_cfd_parser,json=igCfdParser,proto3,oneof"`
+}
+
 func (*Statement_DefaultParser) isStatement_ParserOneof() {}
 
 func (*Statement_T212Parser) isStatement_ParserOneof() {}
@@ -248,6 +381,14 @@ func (*Statement_MsVestParser) isStatement_ParserOneof() {}
 
 func (*Statement_MsWithdrawlParser) isStatement_ParserOneof() {}
 
+func (*Statement_CoinbaseParser) isStatement_ParserOneof() {}
+
+func (*Statement_KrakenParser) isStatement_ParserOneof() {}
+
+func (*Statement_IgSpreadBetParser) isStatement_ParserOneof() {}
+
+func (*Statement_IgCfdParser) isStatement_ParserOneof() {}
+
 type Account struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -256,12 +397,20 @@ type Account struct {
 	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Currency  string `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
 	CgtExempt bool   `protobuf:"varint,3,opt,name=cgt_exempt,json=cgtExempt,proto3" json:"cgt_exempt,omitempty"`
+	// owner of the account. The section 104 pools and CGT are calculated separately for every owner.
+	Owner string `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`
+	// personal_use_fx excludes the foreign currency held in the account from CGT, as it is for personal use
+	// like spending abroad.
+	PersonalUseFx bool `protobuf:"varint,5,opt,name=personal_use_fx,json=personalUseFx,proto3" json:"personal_use_fx,omitempty"`
+	// type of the account, one of GIA, ISA, LISA or SIPP. It is a GIA if not set.
+	// ISA, LISA and SIPP accounts are exempt from CGT.
+	Type string `protobuf:"bytes,6,opt,name=type,proto3" json:"type,omitempty"`
 }
 
 func (x *Account) Reset() {
 	*x = Account{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_statements_proto_msgTypes[2]
+		mi := &file_proto_statements_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -274,7 +423,7 @@ func (x *Account) String() string {
 func (*Account) ProtoMessage() {}
 
 func (x *Account) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_statements_proto_msgTypes[2]
+	mi := &file_proto_statements_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -287,7 +436,7 @@ func (x *Account) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Account.ProtoReflect.Descriptor instead.
 func (*Account) Descriptor() ([]byte, []int) {
-	return file_proto_statements_proto_rawDescGZIP(), []int{2}
+	return file_proto_statements_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Account) GetName() string {
@@ -311,6 +460,27 @@ func (x *Account) GetCgtExempt() bool {
 	return false
 }
 
+func (x *Account) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Account) GetPersonalUseFx() bool {
+	if x != nil {
+		return x.PersonalUseFx
+	}
+	return false
+}
+
+func (x *Account) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
 type T212Parser struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -322,7 +492,7 @@ type T212Parser struct {
 func (x *T212Parser) Reset() {
 	*x = T212Parser{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_statements_proto_msgTypes[3]
+		mi := &file_proto_statements_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -335,7 +505,7 @@ func (x *T212Parser) String() string {
 func (*T212Parser) ProtoMessage() {}
 
 func (x *T212Parser) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_statements_proto_msgTypes[3]
+	mi := &file_proto_statements_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -348,7 +518,7 @@ func (x *T212Parser) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use T212Parser.ProtoReflect.Descriptor instead.
 func (*T212Parser) Descriptor() ([]byte, []int) {
-	return file_proto_statements_proto_rawDescGZIP(), []int{3}
+	return file_proto_statements_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *T212Parser) GetAccount() *Account {
@@ -369,7 +539,7 @@ type IBKRParser struct {
 func (x *IBKRParser) Reset() {
 	*x = IBKRParser{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_statements_proto_msgTypes[4]
+		mi := &file_proto_statements_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -382,7 +552,7 @@ func (x *IBKRParser) String() string {
 func (*IBKRParser) ProtoMessage() {}
 
 func (x *IBKRParser) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_statements_proto_msgTypes[4]
+	mi := &file_proto_statements_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -395,7 +565,7 @@ func (x *IBKRParser) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IBKRParser.ProtoReflect.Descriptor instead.
 func (*IBKRParser) Descriptor() ([]byte, []int) {
-	return file_proto_statements_proto_rawDescGZIP(), []int{4}
+	return file_proto_statements_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *IBKRParser) GetAccount() *Account {
@@ -416,7 +586,7 @@ type IBKRDividendParser struct {
 func (x *IBKRDividendParser) Reset() {
 	*x = IBKRDividendParser{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_statements_proto_msgTypes[5]
+		mi := &file_proto_statements_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -429,7 +599,7 @@ func (x *IBKRDividendParser) String() string {
 func (*IBKRDividendParser) ProtoMessage() {}
 
 func (x *IBKRDividendParser) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_statements_proto_msgTypes[5]
+	mi := &file_proto_statements_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -442,7 +612,7 @@ func (x *IBKRDividendParser) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IBKRDividendParser.ProtoReflect.Descriptor instead.
 func (*IBKRDividendParser) Descriptor() ([]byte, []int) {
-	return file_proto_statements_proto_rawDescGZIP(), []int{5}
+	return file_proto_statements_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *IBKRDividendParser) GetAccount() *Account {
@@ -463,7 +633,7 @@ type IGParser struct {
 func (x *IGParser) Reset() {
 	*x = IGParser{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_statements_proto_msgTypes[6]
+		mi := &file_proto_statements_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -476,7 +646,7 @@ func (x *IGParser) String() string {
 func (*IGParser) ProtoMessage() {}
 
 func (x *IGParser) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_statements_proto_msgTypes[6]
+	mi := &file_proto_statements_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -489,7 +659,7 @@ func (x *IGParser) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IGParser.ProtoReflect.Descriptor instead.
 func (*IGParser) Descriptor() ([]byte, []int) {
-	return file_proto_statements_proto_rawDescGZIP(), []int{6}
+	return file_proto_statements_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *IGParser) GetAccount() *Account {
@@ -510,7 +680,7 @@ type IGDividendParser struct {
 func (x *IGDividendParser) Reset() {
 	*x = IGDividendParser{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_statements_proto_msgTypes[7]
+		mi := &file_proto_statements_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -523,7 +693,7 @@ func (x *IGDividendParser) String() string {
 func (*IGDividendParser) ProtoMessage() {}
 
 func (x *IGDividendParser) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_statements_proto_msgTypes[7]
+	mi := &file_proto_statements_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -536,7 +706,7 @@ func (x *IGDividendParser) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IGDividendParser.ProtoReflect.Descriptor instead.
 func (*IGDividendParser) Descriptor() ([]byte, []int) {
-	return file_proto_statements_proto_rawDescGZIP(), []int{7}
+	return file_proto_statements_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *IGDividendParser) GetAccount() *Account {
@@ -557,7 +727,7 @@ type MSVestParser struct {
 func (x *MSVestParser) Reset() {
 	*x = MSVestParser{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_statements_proto_msgTypes[8]
+		mi := &file_proto_statements_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -570,7 +740,7 @@ func (x *MSVestParser) String() string {
 func (*MSVestParser) ProtoMessage() {}
 
 func (x *MSVestParser) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_statements_proto_msgTypes[8]
+	mi := &file_proto_statements_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -583,7 +753,7 @@ func (x *MSVestParser) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MSVestParser.ProtoReflect.Descriptor instead.
 func (*MSVestParser) Descriptor() ([]byte, []int) {
-	return file_proto_statements_proto_rawDescGZIP(), []int{8}
+	return file_proto_statements_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *MSVestParser) GetAccount() *Account {
@@ -605,7 +775,7 @@ type MSWithdrawlParser struct {
 func (x *MSWithdrawlParser) Reset() {
 	*x = MSWithdrawlParser{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_statements_proto_msgTypes[9]
+		mi := &file_proto_statements_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -618,7 +788,7 @@ func (x *MSWithdrawlParser) String() string {
 func (*MSWithdrawlParser) ProtoMessage() {}
 
 func (x *MSWithdrawlParser) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_statements_proto_msgTypes[9]
+	mi := &file_proto_statements_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -631,7 +801,7 @@ func (x *MSWithdrawlParser) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MSWithdrawlParser.ProtoReflect.Descriptor instead.
 func (*MSWithdrawlParser) Descriptor() ([]byte, []int) {
-	return file_proto_statements_proto_rawDescGZIP(), []int{9}
+	return file_proto_statements_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *MSWithdrawlParser) GetAccount() *Account {
@@ -648,6 +818,199 @@ func (x *MSWithdrawlParser) GetWithdrawAccount() *Account {
 	return nil
 }
 
+// CoinbaseParser parses the transaction history CSV of Coinbase, with the lines before the header removed
+type CoinbaseParser struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Account *Account `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+}
+
+func (x *CoinbaseParser) Reset() {
+	*x = CoinbaseParser{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_statements_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CoinbaseParser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoinbaseParser) ProtoMessage() {}
+
+func (x *CoinbaseParser) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_statements_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoinbaseParser.ProtoReflect.Descriptor instead.
+func (*CoinbaseParser) Descriptor() ([]byte, []int) {
+	return file_proto_statements_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CoinbaseParser) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+// KrakenParser parses the trades CSV export of Kraken. A crypto to crypto trade needs the GBP price of the
+// quote asset in an extra "quote gbp price" column at the end.
+type KrakenParser struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Account *Account `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+}
+
+func (x *KrakenParser) Reset() {
+	*x = KrakenParser{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_statements_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KrakenParser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KrakenParser) ProtoMessage() {}
+
+func (x *KrakenParser) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_statements_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KrakenParser.ProtoReflect.Descriptor instead.
+func (*KrakenParser) Descriptor() ([]byte, []int) {
+	return file_proto_statements_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *KrakenParser) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+// IGSpreadBetParser parses the closed positions in the transaction history of an IG spread betting account
+type IGSpreadBetParser struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Account *Account `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+}
+
+func (x *IGSpreadBetParser) Reset() {
+	*x = IGSpreadBetParser{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_statements_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IGSpreadBetParser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IGSpreadBetParser) ProtoMessage() {}
+
+func (x *IGSpreadBetParser) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_statements_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IGSpreadBetParser.ProtoReflect.Descriptor instead.
+func (*IGSpreadBetParser) Descriptor() ([]byte, []int) {
+	return file_proto_statements_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *IGSpreadBetParser) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+// IGCFDParser parses the closed positions in the transaction history of an IG CFD account
+type IGCFDParser struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Account *Account `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+}
+
+func (x *IGCFDParser) Reset() {
+	*x = IGCFDParser{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_statements_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IGCFDParser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IGCFDParser) ProtoMessage() {}
+
+func (x *IGCFDParser) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_statements_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IGCFDParser.ProtoReflect.Descriptor instead.
+func (*IGCFDParser) Descriptor() ([]byte, []int) {
+	return file_proto_statements_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *IGCFDParser) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
 type DefaultParser struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -657,7 +1020,7 @@ type DefaultParser struct {
 func (x *DefaultParser) Reset() {
 	*x = DefaultParser{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_statements_proto_msgTypes[10]
+		mi := &file_proto_statements_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -670,7 +1033,7 @@ func (x *DefaultParser) String() string {
 func (*DefaultParser) ProtoMessage() {}
 
 func (x *DefaultParser) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_statements_proto_msgTypes[10]
+	mi := &file_proto_statements_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -683,7 +1046,7 @@ func (x *DefaultParser) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DefaultParser.ProtoReflect.Descriptor instead.
 func (*DefaultParser) Descriptor() ([]byte, []int) {
-	return file_proto_statements_proto_rawDescGZIP(), []int{10}
+	return file_proto_statements_proto_rawDescGZIP(), []int{15}
 }
 
 var File_proto_statements_proto protoreflect.FileDescriptor
@@ -691,96 +1054,149 @@ var File_proto_statements_proto protoreflect.FileDescriptor
 var file_proto_statements_proto_rawDesc = []byte{
 	0x0a, 0x16, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e,
 	0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79,
-	0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x22, 0x47, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x74,
+	0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x22, 0x7d, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x74,
 	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x6d,
 	0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x61, 0x61, 0x67,
 	0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x74,
 	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74,
-	0x73, 0x22, 0xa1, 0x05, 0x0a, 0x09, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12,
-	0x46, 0x0a, 0x0e, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x70, 0x61, 0x72, 0x73, 0x65,
-	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79,
-	0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74,
-	0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x0d, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c,
-	0x74, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x3d, 0x0a, 0x0b, 0x74, 0x32, 0x31, 0x32, 0x5f,
-	0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61,
-	0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x54, 0x32,
-	0x31, 0x32, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x0a, 0x74, 0x32, 0x31, 0x32,
-	0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x3d, 0x0a, 0x0b, 0x69, 0x62, 0x6b, 0x72, 0x5f, 0x70,
-	0x61, 0x72, 0x73, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x61,
-	0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x49, 0x42, 0x4b,
-	0x52, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x0a, 0x69, 0x62, 0x6b, 0x72, 0x50,
-	0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x56, 0x0a, 0x14, 0x69, 0x62, 0x6b, 0x72, 0x5f, 0x64, 0x69,
-	0x76, 0x69, 0x64, 0x65, 0x6e, 0x64, 0x5f, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x18, 0x08, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72,
-	0x61, 0x64, 0x65, 0x73, 0x2e, 0x49, 0x42, 0x4b, 0x52, 0x44, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e,
-	0x64, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x12, 0x69, 0x62, 0x6b, 0x72, 0x44,
-	0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x37, 0x0a,
-	0x09, 0x69, 0x67, 0x5f, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x18, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65,
-	0x73, 0x2e, 0x49, 0x47, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x08, 0x69, 0x67,
-	0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x50, 0x0a, 0x12, 0x69, 0x67, 0x5f, 0x64, 0x69, 0x76,
-	0x69, 0x64, 0x65, 0x6e, 0x64, 0x5f, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x20, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61,
-	0x64, 0x65, 0x73, 0x2e, 0x49, 0x47, 0x44, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64, 0x50, 0x61,
-	0x72, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x10, 0x69, 0x67, 0x44, 0x69, 0x76, 0x69, 0x64, 0x65,
-	0x6e, 0x64, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x44, 0x0a, 0x0e, 0x6d, 0x73, 0x5f, 0x76,
-	0x65, 0x73, 0x74, 0x5f, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1c, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65,
-	0x73, 0x2e, 0x4d, 0x53, 0x56, 0x65, 0x73, 0x74, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x48, 0x00,
-	0x52, 0x0c, 0x6d, 0x73, 0x56, 0x65, 0x73, 0x74, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x53,
-	0x0a, 0x13, 0x6d, 0x73, 0x5f, 0x77, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x6c, 0x5f, 0x70,
-	0x61, 0x72, 0x73, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x61, 0x61,
-	0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x4d, 0x53, 0x57,
-	0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x6c, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x48, 0x00,
-	0x52, 0x11, 0x6d, 0x73, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x6c, 0x50, 0x61, 0x72,
-	0x73, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
-	0x18, 0x64, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72,
-	0x79, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x65,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x42,
-	0x0e, 0x0a, 0x0c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x5f, 0x6f, 0x6e, 0x65, 0x6f, 0x66, 0x4a,
-	0x04, 0x08, 0x09, 0x10, 0x64, 0x22, 0x58, 0x0a, 0x07, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
-	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
-	0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x67, 0x74, 0x5f, 0x65, 0x78, 0x65, 0x6d, 0x70, 0x74, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x67, 0x74, 0x45, 0x78, 0x65, 0x6d, 0x70, 0x74, 0x22,
-	0x3f, 0x0a, 0x0a, 0x54, 0x32, 0x31, 0x32, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x31, 0x0a,
+	0x73, 0x12, 0x34, 0x0a, 0x09, 0x74, 0x61, 0x78, 0x5f, 0x79, 0x65, 0x61, 0x72, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74,
+	0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x78, 0x59, 0x65, 0x61, 0x72, 0x52, 0x08, 0x74,
+	0x61, 0x78, 0x59, 0x65, 0x61, 0x72, 0x73, 0x22, 0x81, 0x01, 0x0a, 0x07, 0x54, 0x61, 0x78, 0x59,
+	0x65, 0x61, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x61, 0x78, 0x61, 0x62,
+	0x6c, 0x65, 0x5f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0d, 0x74, 0x61, 0x78, 0x61, 0x62, 0x6c, 0x65, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x25,
+	0x0a, 0x0e, 0x6c, 0x6f, 0x73, 0x73, 0x65, 0x73, 0x5f, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x65, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x6c, 0x6f, 0x73, 0x73, 0x65, 0x73, 0x43, 0x6c,
+	0x61, 0x69, 0x6d, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x22, 0xca, 0x07, 0x0a, 0x09,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x46, 0x0a, 0x0e, 0x64, 0x65, 0x66,
+	0x61, 0x75, 0x6c, 0x74, 0x5f, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1d, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64,
+	0x65, 0x73, 0x2e, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x48, 0x00, 0x52, 0x0d, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x50, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x12, 0x3d, 0x0a, 0x0b, 0x74, 0x32, 0x31, 0x32, 0x5f, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a,
+	0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x54, 0x32, 0x31, 0x32, 0x50, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x48, 0x00, 0x52, 0x0a, 0x74, 0x32, 0x31, 0x32, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x12, 0x3d, 0x0a, 0x0b, 0x69, 0x62, 0x6b, 0x72, 0x5f, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e,
+	0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x49, 0x42, 0x4b, 0x52, 0x50, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x48, 0x00, 0x52, 0x0a, 0x69, 0x62, 0x6b, 0x72, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12,
+	0x56, 0x0a, 0x14, 0x69, 0x62, 0x6b, 0x72, 0x5f, 0x64, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64,
+	0x5f, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e,
+	0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x49,
+	0x42, 0x4b, 0x52, 0x44, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x48, 0x00, 0x52, 0x12, 0x69, 0x62, 0x6b, 0x72, 0x44, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e,
+	0x64, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x37, 0x0a, 0x09, 0x69, 0x67, 0x5f, 0x70, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x61, 0x67,
+	0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x49, 0x47, 0x50, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x08, 0x69, 0x67, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x12, 0x50, 0x0a, 0x12, 0x69, 0x67, 0x5f, 0x64, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64, 0x5f,
+	0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x61,
+	0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x49, 0x47,
+	0x44, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x48, 0x00,
+	0x52, 0x10, 0x69, 0x67, 0x44, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x12, 0x44, 0x0a, 0x0e, 0x6d, 0x73, 0x5f, 0x76, 0x65, 0x73, 0x74, 0x5f, 0x70, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x61, 0x67,
+	0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x4d, 0x53, 0x56, 0x65,
+	0x73, 0x74, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x0c, 0x6d, 0x73, 0x56, 0x65,
+	0x73, 0x74, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x53, 0x0a, 0x13, 0x6d, 0x73, 0x5f, 0x77,
+	0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x6c, 0x5f, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e,
+	0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x4d, 0x53, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61,
+	0x77, 0x6c, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x11, 0x6d, 0x73, 0x57, 0x69,
+	0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x6c, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x49, 0x0a,
+	0x0f, 0x63, 0x6f, 0x69, 0x6e, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a,
+	0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x43, 0x6f, 0x69, 0x6e, 0x62, 0x61, 0x73, 0x65,
+	0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x0e, 0x63, 0x6f, 0x69, 0x6e, 0x62, 0x61,
+	0x73, 0x65, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x43, 0x0a, 0x0d, 0x6b, 0x72, 0x61, 0x6b,
+	0x65, 0x6e, 0x5f, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73,
+	0x2e, 0x4b, 0x72, 0x61, 0x6b, 0x65, 0x6e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52,
+	0x0c, 0x6b, 0x72, 0x61, 0x6b, 0x65, 0x6e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x54, 0x0a,
+	0x14, 0x69, 0x67, 0x5f, 0x73, 0x70, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x62, 0x65, 0x74, 0x5f, 0x70,
+	0x61, 0x72, 0x73, 0x65, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x61, 0x61,
+	0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x49, 0x47, 0x53,
+	0x70, 0x72, 0x65, 0x61, 0x64, 0x42, 0x65, 0x74, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x48, 0x00,
+	0x52, 0x11, 0x69, 0x67, 0x53, 0x70, 0x72, 0x65, 0x61, 0x64, 0x42, 0x65, 0x74, 0x50, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x12, 0x41, 0x0a, 0x0d, 0x69, 0x67, 0x5f, 0x63, 0x66, 0x64, 0x5f, 0x70, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x61, 0x61, 0x67,
+	0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x49, 0x47, 0x43, 0x46,
+	0x44, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x0b, 0x69, 0x67, 0x43, 0x66, 0x64,
+	0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x79, 0x18, 0x64, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65,
+	0x73, 0x18, 0x65, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d,
+	0x65, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x5f, 0x6f, 0x6e, 0x65,
+	0x6f, 0x66, 0x4a, 0x04, 0x08, 0x0d, 0x10, 0x64, 0x22, 0xaa, 0x01, 0x0a, 0x07, 0x41, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x67, 0x74, 0x5f, 0x65, 0x78, 0x65, 0x6d,
+	0x70, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x67, 0x74, 0x45, 0x78, 0x65,
+	0x6d, 0x70, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x26, 0x0a, 0x0f, 0x70, 0x65, 0x72,
+	0x73, 0x6f, 0x6e, 0x61, 0x6c, 0x5f, 0x75, 0x73, 0x65, 0x5f, 0x66, 0x78, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0d, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x61, 0x6c, 0x55, 0x73, 0x65, 0x46,
+	0x78, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x3f, 0x0a, 0x0a, 0x54, 0x32, 0x31, 0x32, 0x50, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74,
+	0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x07, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x3f, 0x0a, 0x0a, 0x49, 0x42, 0x4b, 0x52, 0x50, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e,
+	0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x07,
+	0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x47, 0x0a, 0x12, 0x49, 0x42, 0x4b, 0x52, 0x44,
+	0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x31, 0x0a,
 	0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
 	0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e,
 	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
-	0x22, 0x3f, 0x0a, 0x0a, 0x49, 0x42, 0x4b, 0x52, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x31,
-	0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x17, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73,
-	0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e,
-	0x74, 0x22, 0x47, 0x0a, 0x12, 0x49, 0x42, 0x4b, 0x52, 0x44, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e,
-	0x64, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75,
-	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78,
-	0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e,
-	0x74, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x3d, 0x0a, 0x08, 0x49, 0x47,
+	0x22, 0x3d, 0x0a, 0x08, 0x49, 0x47, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x07,
+	0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x41,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22,
+	0x45, 0x0a, 0x10, 0x49, 0x47, 0x44, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74,
+	0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x07, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x41, 0x0a, 0x0c, 0x4d, 0x53, 0x56, 0x65, 0x73, 0x74,
 	0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e,
 	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79,
 	0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
-	0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x45, 0x0a, 0x10, 0x49, 0x47, 0x44,
-	0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x31, 0x0a,
-	0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
-	0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e,
-	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
-	0x22, 0x41, 0x0a, 0x0c, 0x4d, 0x53, 0x56, 0x65, 0x73, 0x74, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72,
-	0x12, 0x31, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x17, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64,
-	0x65, 0x73, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f,
-	0x75, 0x6e, 0x74, 0x22, 0x8a, 0x01, 0x0a, 0x11, 0x4d, 0x53, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72,
-	0x61, 0x77, 0x6c, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x07, 0x61, 0x63, 0x63,
-	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x61, 0x67,
-	0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x41, 0x63, 0x63, 0x6f,
-	0x75, 0x6e, 0x74, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x42, 0x0a, 0x10,
-	0x77, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a,
+	0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x8a, 0x01, 0x0a, 0x11, 0x4d, 0x53,
+	0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x6c, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12,
+	0x31, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65,
+	0x73, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x42, 0x0a, 0x10, 0x77, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x5f, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61,
+	0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x41, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x0f, 0x77, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x41,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x43, 0x0a, 0x0e, 0x43, 0x6f, 0x69, 0x6e, 0x62, 0x61,
+	0x73, 0x65, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x61, 0x67, 0x72,
+	0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x41, 0x0a, 0x0c, 0x4b,
+	0x72, 0x61, 0x6b, 0x65, 0x6e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x07, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61,
+	0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x41, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x46,
+	0x0a, 0x11, 0x49, 0x47, 0x53, 0x70, 0x72, 0x65, 0x61, 0x64, 0x42, 0x65, 0x74, 0x50, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a, 0x2e, 0x74,
+	0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x07, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x40, 0x0a, 0x0b, 0x49, 0x47, 0x43, 0x46, 0x44, 0x50,
+	0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x61, 0x61, 0x67, 0x72, 0x78, 0x79, 0x7a,
 	0x2e, 0x74, 0x72, 0x61, 0x64, 0x65, 0x73, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52,
-	0x0f, 0x77, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
-	0x22, 0x0f, 0x0a, 0x0d, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x50, 0x61, 0x72, 0x73, 0x65,
-	0x72, 0x42, 0x14, 0x5a, 0x12, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x65,
-	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x0f, 0x0a, 0x0d, 0x44, 0x65, 0x66, 0x61,
+	0x75, 0x6c, 0x74, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x42, 0x14, 0x5a, 0x12, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x70, 0x62, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -795,43 +1211,57 @@ func file_proto_statements_proto_rawDescGZIP() []byte {
 	return file_proto_statements_proto_rawDescData
 }
 
-var file_proto_statements_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_proto_statements_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_proto_statements_proto_goTypes = []interface{}{
 	(*Statements)(nil),         // 0: aagrxyz.trades.Statements
-	(*Statement)(nil),          // 1: aagrxyz.trades.Statement
-	(*Account)(nil),            // 2: aagrxyz.trades.Account
-	(*T212Parser)(nil),         // 3: aagrxyz.trades.T212Parser
-	(*IBKRParser)(nil),         // 4: aagrxyz.trades.IBKRParser
-	(*IBKRDividendParser)(nil), // 5: aagrxyz.trades.IBKRDividendParser
-	(*IGParser)(nil),           // 6: aagrxyz.trades.IGParser
-	(*IGDividendParser)(nil),   // 7: aagrxyz.trades.IGDividendParser
-	(*MSVestParser)(nil),       // 8: aagrxyz.trades.MSVestParser
-	(*MSWithdrawlParser)(nil),  // 9: aagrxyz.trades.MSWithdrawlParser
-	(*DefaultParser)(nil),      // 10: aagrxyz.trades.DefaultParser
+	(*TaxYear)(nil),            // 1: aagrxyz.trades.TaxYear
+	(*Statement)(nil),          // 2: aagrxyz.trades.Statement
+	(*Account)(nil),            // 3: aagrxyz.trades.Account
+	(*T212Parser)(nil),         // 4: aagrxyz.trades.T212Parser
+	(*IBKRParser)(nil),         // 5: aagrxyz.trades.IBKRParser
+	(*IBKRDividendParser)(nil), // 6: aagrxyz.trades.IBKRDividendParser
+	(*IGParser)(nil),           // 7: aagrxyz.trades.IGParser
+	(*IGDividendParser)(nil),   // 8: aagrxyz.trades.IGDividendParser
+	(*MSVestParser)(nil),       // 9: aagrxyz.trades.MSVestParser
+	(*MSWithdrawlParser)(nil),  // 10: aagrxyz.trades.MSWithdrawlParser
+	(*CoinbaseParser)(nil),     // 11: aagrxyz.trades.CoinbaseParser
+	(*KrakenParser)(nil),       // 12: aagrxyz.trades.KrakenParser
+	(*IGSpreadBetParser)(nil),  // 13: aagrxyz.trades.IGSpreadBetParser
+	(*IGCFDParser)(nil),        // 14: aagrxyz.trades.IGCFDParser
+	(*DefaultParser)(nil),      // 15: aagrxyz.trades.DefaultParser
 }
 var file_proto_statements_proto_depIdxs = []int32{
-	1,  // 0: aagrxyz.trades.Statements.statements:type_name -> aagrxyz.trades.Statement
-	10, // 1: aagrxyz.trades.Statement.default_parser:type_name -> aagrxyz.trades.DefaultParser
-	3,  // 2: aagrxyz.trades.Statement.t212_parser:type_name -> aagrxyz.trades.T212Parser
-	4,  // 3: aagrxyz.trades.Statement.ibkr_parser:type_name -> aagrxyz.trades.IBKRParser
-	5,  // 4: aagrxyz.trades.Statement.ibkr_dividend_parser:type_name -> aagrxyz.trades.IBKRDividendParser
-	6,  // 5: aagrxyz.trades.Statement.ig_parser:type_name -> aagrxyz.trades.IGParser
-	7,  // 6: aagrxyz.trades.Statement.ig_dividend_parser:type_name -> aagrxyz.trades.IGDividendParser
-	8,  // 7: aagrxyz.trades.Statement.ms_vest_parser:type_name -> aagrxyz.trades.MSVestParser
-	9,  // 8: aagrxyz.trades.Statement.ms_withdrawl_parser:type_name -> aagrxyz.trades.MSWithdrawlParser
-	2,  // 9: aagrxyz.trades.T212Parser.account:type_name -> aagrxyz.trades.Account
-	2,  // 10: aagrxyz.trades.IBKRParser.account:type_name -> aagrxyz.trades.Account
-	2,  // 11: aagrxyz.trades.IBKRDividendParser.account:type_name -> aagrxyz.trades.Account
-	2,  // 12: aagrxyz.trades.IGParser.account:type_name -> aagrxyz.trades.Account
-	2,  // 13: aagrxyz.trades.IGDividendParser.account:type_name -> aagrxyz.trades.Account
-	2,  // 14: aagrxyz.trades.MSVestParser.account:type_name -> aagrxyz.trades.Account
-	2,  // 15: aagrxyz.trades.MSWithdrawlParser.account:type_name -> aagrxyz.trades.Account
-	2,  // 16: aagrxyz.trades.MSWithdrawlParser.withdraw_account:type_name -> aagrxyz.trades.Account
-	17, // [17:17] is the sub-list for method output_type
-	17, // [17:17] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
+	2,  // 0: aagrxyz.trades.Statements.statements:type_name -> aagrxyz.trades.Statement
+	1,  // 1: aagrxyz.trades.Statements.tax_years:type_name -> aagrxyz.trades.TaxYear
+	15, // 2: aagrxyz.trades.Statement.default_parser:type_name -> aagrxyz.trades.DefaultParser
+	4,  // 3: aagrxyz.trades.Statement.t212_parser:type_name -> aagrxyz.trades.T212Parser
+	5,  // 4: aagrxyz.trades.Statement.ibkr_parser:type_name -> aagrxyz.trades.IBKRParser
+	6,  // 5: aagrxyz.trades.Statement.ibkr_dividend_parser:type_name -> aagrxyz.trades.IBKRDividendParser
+	7,  // 6: aagrxyz.trades.Statement.ig_parser:type_name -> aagrxyz.trades.IGParser
+	8,  // 7: aagrxyz.trades.Statement.ig_dividend_parser:type_name -> aagrxyz.trades.IGDividendParser
+	9,  // 8: aagrxyz.trades.Statement.ms_vest_parser:type_name -> aagrxyz.trades.MSVestParser
+	10, // 9: aagrxyz.trades.Statement.ms_withdrawl_parser:type_name -> aagrxyz.trades.MSWithdrawlParser
+	11, // 10: aagrxyz.trades.Statement.coinbase_parser:type_name -> aagrxyz.trades.CoinbaseParser
+	12, // 11: aagrxyz.trades.Statement.kraken_parser:type_name -> aagrxyz.trades.KrakenParser
+	13, // 12: aagrxyz.trades.Statement.ig_spread_bet_parser:type_name -> aagrxyz.trades.IGSpreadBetParser
+	14, // 13: aagrxyz.trades.Statement.ig_cfd_parser:type_name -> aagrxyz.trades.IGCFDParser
+	3,  // 14: aagrxyz.trades.T212Parser.account:type_name -> aagrxyz.trades.Account
+	3,  // 15: aagrxyz.trades.IBKRParser.account:type_name -> aagrxyz.trades.Account
+	3,  // 16: aagrxyz.trades.IBKRDividendParser.account:type_name -> aagrxyz.trades.Account
+	3,  // 17: aagrxyz.trades.IGParser.account:type_name -> aagrxyz.trades.Account
+	3,  // 18: aagrxyz.trades.IGDividendParser.account:type_name -> aagrxyz.trades.Account
+	3,  // 19: aagrxyz.trades.MSVestParser.account:type_name -> aagrxyz.trades.Account
+	3,  // 20: aagrxyz.trades.MSWithdrawlParser.account:type_name -> aagrxyz.trades.Account
+	3,  // 21: aagrxyz.trades.MSWithdrawlParser.withdraw_account:type_name -> aagrxyz.trades.Account
+	3,  // 22: aagrxyz.trades.CoinbaseParser.account:type_name -> aagrxyz.trades.Account
+	3,  // 23: aagrxyz.trades.KrakenParser.account:type_name -> aagrxyz.trades.Account
+	3,  // 24: aagrxyz.trades.IGSpreadBetParser.account:type_name -> aagrxyz.trades.Account
+	3,  // 25: aagrxyz.trades.IGCFDParser.account:type_name -> aagrxyz.trades.Account
+	26, // [26:26] is the sub-list for method output_type
+	26, // [26:26] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_proto_statements_proto_init() }
@@ -853,7 +1283,7 @@ func file_proto_statements_proto_init() {
 			}
 		}
 		file_proto_statements_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Statement); i {
+			switch v := v.(*TaxYear); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -865,7 +1295,7 @@ func file_proto_statements_proto_init() {
 			}
 		}
 		file_proto_statements_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Account); i {
+			switch v := v.(*Statement); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -877,7 +1307,7 @@ func file_proto_statements_proto_init() {
 			}
 		}
 		file_proto_statements_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*T212Parser); i {
+			switch v := v.(*Account); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -889,7 +1319,7 @@ func file_proto_statements_proto_init() {
 			}
 		}
 		file_proto_statements_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*IBKRParser); i {
+			switch v := v.(*T212Parser); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -901,7 +1331,7 @@ func file_proto_statements_proto_init() {
 			}
 		}
 		file_proto_statements_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*IBKRDividendParser); i {
+			switch v := v.(*IBKRParser); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -913,7 +1343,7 @@ func file_proto_statements_proto_init() {
 			}
 		}
 		file_proto_statements_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*IGParser); i {
+			switch v := v.(*IBKRDividendParser); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -925,7 +1355,7 @@ func file_proto_statements_proto_init() {
 			}
 		}
 		file_proto_statements_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*IGDividendParser); i {
+			switch v := v.(*IGParser); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -937,7 +1367,7 @@ func file_proto_statements_proto_init() {
 			}
 		}
 		file_proto_statements_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MSVestParser); i {
+			switch v := v.(*IGDividendParser); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -949,7 +1379,7 @@ func file_proto_statements_proto_init() {
 			}
 		}
 		file_proto_statements_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MSWithdrawlParser); i {
+			switch v := v.(*MSVestParser); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -961,6 +1391,66 @@ func file_proto_statements_proto_init() {
 			}
 		}
 		file_proto_statements_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MSWithdrawlParser); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_statements_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CoinbaseParser); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_statements_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KrakenParser); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_statements_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IGSpreadBetParser); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_statements_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IGCFDParser); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_statements_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DefaultParser); i {
 			case 0:
 				return &v.state
@@ -973,7 +1463,7 @@ func file_proto_statements_proto_init() {
 			}
 		}
 	}
-	file_proto_statements_proto_msgTypes[1].OneofWrappers = []interface{}{
+	file_proto_statements_proto_msgTypes[2].OneofWrappers = []interface{}{
 		(*Statement_DefaultParser)(nil),
 		(*Statement_T212Parser)(nil),
 		(*Statement_IbkrParser)(nil),
@@ -982,6 +1472,10 @@ func file_proto_statements_proto_init() {
 		(*Statement_IgDividendParser)(nil),
 		(*Statement_MsVestParser)(nil),
 		(*Statement_MsWithdrawlParser)(nil),
+		(*Statement_CoinbaseParser)(nil),
+		(*Statement_KrakenParser)(nil),
+		(*Statement_IgSpreadBetParser)(nil),
+		(*Statement_IgCfdParser)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -989,7 +1483,7 @@ func file_proto_statements_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_statements_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,216 @@
+package record
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CostAdjustmentDetails stores the details of a COSTADJUSTMENT record parsed from its description
+type CostAdjustmentDetails struct {
+	// Equalisation payments always reduce the cost of the holding
+	Equalisation bool
+	// MarketValue in GBP of the holding just after a capital distribution, 0 if not known.
+	// It is needed to tell whether the distribution is small, and to part dispose of the holding if not.
+	MarketValue float64
+}
+
+// ParseCostAdjustment parses the description of a COSTADJUSTMENT record, which is one of
+// "EQUALISATION", "DISTRIBUTION" or "DISTRIBUTION @ <market value in GBP>"
+func ParseCostAdjustment(desc string) (*CostAdjustmentDetails, error) {
+	desc = strings.ToUpper(strings.TrimSpace(desc))
+	if desc == "EQUALISATION" {
+		return &CostAdjustmentDetails{Equalisation: true}, nil
+	}
+	kind, value, found := strings.Cut(desc, "@")
+	if strings.TrimSpace(kind) != "DISTRIBUTION" {
+		return nil, fmt.Errorf("invalid cost adjustment %q, want EQUALISATION or DISTRIBUTION @ <market value>", desc)
+	}
+	res := &CostAdjustmentDetails{}
+	if !found {
+		return res, nil
+	}
+	var err error
+	res.MarketValue, err = strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %v to market value as float: %v", value, err)
+	}
+	if res.MarketValue < 0.0 {
+		return nil, fmt.Errorf("market value cannot be negative: %q", desc)
+	}
+	return res, nil
+}
+
+// TakeoverDetails stores the details of a TAKEOVER record parsed from its description
+type TakeoverDetails struct {
+	NewTicker string
+	// NewCount new shares are received for every OldCount old shares
+	NewCount, OldCount float64
+	// CashPerShare is the cash in GBP received for every old share
+	CashPerShare float64
+	// NewSharePrice is the market value in GBP of a new share, used to apportion the cost
+	// between the new shares and the cash
+	NewSharePrice float64
+}
+
+// ParseTakeover parses the description of a TAKEOVER record, which is
+// "<new ticker> <new count> FOR <old count>", optionally followed by
+// "CASH <cash per old share> @ <price of a new share>" with the amounts in GBP.
+// A cash only takeover is "<new ticker> 0 FOR 1 CASH <cash per old share>".
+func ParseTakeover(desc string) (*TakeoverDetails, error) {
+	fields := strings.Fields(strings.ToUpper(desc))
+	if (len(fields) != 4 && len(fields) != 6 && len(fields) != 8) || fields[2] != "FOR" {
+		return nil, fmt.Errorf("invalid takeover %q, want <NEW> <a> FOR <b> [CASH <cash per share> @ <new share price>]", desc)
+	}
+	res := &TakeoverDetails{NewTicker: fields[0]}
+	var err error
+	if res.NewCount, err = strconv.ParseFloat(fields[1], 64); err != nil {
+		return nil, fmt.Errorf("cannot convert %v to new count as float: %v", fields[1], err)
+	}
+	if res.OldCount, err = strconv.ParseFloat(fields[3], 64); err != nil {
+		return nil, fmt.Errorf("cannot convert %v to old count as float: %v", fields[3], err)
+	}
+	if res.NewCount < 0.0 || res.OldCount <= 0.0 {
+		return nil, fmt.Errorf("invalid ratio in takeover %q", desc)
+	}
+	if len(fields) > 4 {
+		if fields[4] != "CASH" {
+			return nil, fmt.Errorf("invalid takeover %q, want CASH after the ratio", desc)
+		}
+		if res.CashPerShare, err = strconv.ParseFloat(fields[5], 64); err != nil {
+			return nil, fmt.Errorf("cannot convert %v to cash per share as float: %v", fields[5], err)
+		}
+	}
+	if len(fields) > 6 {
+		if fields[6] != "@" {
+			return nil, fmt.Errorf("invalid takeover %q, want @ before the new share price", desc)
+		}
+		if res.NewSharePrice, err = strconv.ParseFloat(fields[7], 64); err != nil {
+			return nil, fmt.Errorf("cannot convert %v to new share price as float: %v", fields[7], err)
+		}
+	}
+	if res.CashPerShare > 0.0 && res.NewCount > 0.0 && res.NewSharePrice <= 0.0 {
+		return nil, fmt.Errorf("takeover %q with both shares and cash needs the new share price", desc)
+	}
+	return res, nil
+}
+
+// CashFraction returns the part of the cost of the old shares which is apportioned to the cash
+func (t *TakeoverDetails) CashFraction() float64 {
+	cash := t.CashPerShare * t.OldCount
+	value := t.NewSharePrice * t.NewCount
+	if cash <= 0.0 {
+		return 0.0
+	}
+	return cash / (cash + value)
+}
+
+// DemergerDetails stores the details of a DEMERGER record parsed from its description
+type DemergerDetails struct {
+	NewTicker string
+	// NewCount new shares are received for every OldCount shares held
+	NewCount, OldCount float64
+	// OldSharePrice and NewSharePrice are the market values in GBP of a share of each ticker on the
+	// first day of dealing after the demerger, used to apportion the cost. 0 if not known yet.
+	OldSharePrice, NewSharePrice float64
+}
+
+// ParseDemerger parses the description of a DEMERGER record, which is
+// "<new ticker> <new count> FOR <old count>", optionally followed by
+// "@ <price of an old share> <price of a new share>" with the prices in GBP.
+func ParseDemerger(desc string) (*DemergerDetails, error) {
+	fields := strings.Fields(strings.ToUpper(desc))
+	if (len(fields) != 4 && len(fields) != 7) || fields[2] != "FOR" {
+		return nil, fmt.Errorf("invalid demerger %q, want <NEW> <a> FOR <b> [@ <old share price> <new share price>]", desc)
+	}
+	res := &DemergerDetails{NewTicker: fields[0]}
+	var err error
+	if res.NewCount, err = strconv.ParseFloat(fields[1], 64); err != nil {
+		return nil, fmt.Errorf("cannot convert %v to new count as float: %v", fields[1], err)
+	}
+	if res.OldCount, err = strconv.ParseFloat(fields[3], 64); err != nil {
+		return nil, fmt.Errorf("cannot convert %v to old count as float: %v", fields[3], err)
+	}
+	if res.NewCount <= 0.0 || res.OldCount <= 0.0 {
+		return nil, fmt.Errorf("invalid ratio in demerger %q", desc)
+	}
+	if len(fields) > 4 {
+		if fields[4] != "@" {
+			return nil, fmt.Errorf("invalid demerger %q, want @ before the share prices", desc)
+		}
+		if res.OldSharePrice, err = strconv.ParseFloat(fields[5], 64); err != nil {
+			return nil, fmt.Errorf("cannot convert %v to old share price as float: %v", fields[5], err)
+		}
+		if res.NewSharePrice, err = strconv.ParseFloat(fields[6], 64); err != nil {
+			return nil, fmt.Errorf("cannot convert %v to new share price as float: %v", fields[6], err)
+		}
+		if res.OldSharePrice <= 0.0 || res.NewSharePrice <= 0.0 {
+			return nil, fmt.Errorf("invalid share prices in demerger %q", desc)
+		}
+	}
+	return res, nil
+}
+
+// HasPrices returns whether the market values needed to apportion the cost are known
+func (d *DemergerDetails) HasPrices() bool {
+	return d.OldSharePrice > 0.0 && d.NewSharePrice > 0.0
+}
+
+// NewFraction returns the part of the cost of the old shares which is apportioned to the new shares
+func (d *DemergerDetails) NewFraction() float64 {
+	old := d.OldSharePrice * d.OldCount
+	value := d.NewSharePrice * d.NewCount
+	if old+value <= 0.0 {
+		return 0.0
+	}
+	return value / (old + value)
+}
+
+func (d *DemergerDetails) String() string {
+	res := fmt.Sprintf("%s %s FOR %s", d.NewTicker,
+		strconv.FormatFloat(d.NewCount, 'f', -1, 64), strconv.FormatFloat(d.OldCount, 'f', -1, 64))
+	if d.HasPrices() {
+		res += fmt.Sprintf(" @ %s %s",
+			strconv.FormatFloat(d.OldSharePrice, 'f', -1, 64), strconv.FormatFloat(d.NewSharePrice, 'f', -1, 64))
+	}
+	return res
+}
+
+// ParseSplit parses the description of a SPLIT record, which is "<new count> FOR <old count>".
+// The counts can be fractional, like "1.5 FOR 1", and a reverse split is like "1 FOR 10".
+// It returns the ratio of the new shares to the old shares.
+func ParseSplit(desc string) (float64, error) {
+	fields := strings.Fields(strings.ToUpper(desc))
+	if len(fields) != 3 || fields[1] != "FOR" {
+		return 0.0, fmt.Errorf("invalid split %q, want <a> FOR <b>", desc)
+	}
+	newCt, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0.0, fmt.Errorf("cannot convert %v to new count as float: %v", fields[0], err)
+	}
+	oldCt, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return 0.0, fmt.Errorf("cannot convert %v to old count as float: %v", fields[2], err)
+	}
+	if newCt <= 0.0 || oldCt <= 0.0 {
+		return 0.0, fmt.Errorf("invalid ratio in split %q", desc)
+	}
+	return newCt / oldCt, nil
+}
+
+// ParseNegligibleValue parses the description of a NEGLIGIBLEVALUE record, which is the value in GBP
+// of a share at the time of the claim. An empty description is a nil value.
+func ParseNegligibleValue(desc string) (float64, error) {
+	desc = strings.TrimSpace(desc)
+	if desc == "" {
+		return 0.0, nil
+	}
+	value, err := strconv.ParseFloat(desc, 64)
+	if err != nil {
+		return 0.0, fmt.Errorf("cannot convert %v to value per share as float: %v", desc, err)
+	}
+	if value < 0.0 {
+		return 0.0, fmt.Errorf("invalid value per share %f", value)
+	}
+	return value, nil
+}
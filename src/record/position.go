@@ -0,0 +1,91 @@
+package record
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ClosedPositionDetails stores the details of a CLOSEDPOSITION record parsed from its description
+type ClosedPositionDetails struct {
+	// Instrument is either SPREAD_BET_ASSET, whose profit is exempt from CGT, or CFD_ASSET
+	Instrument AssetType
+	Opened     time.Time
+}
+
+// ParseClosedPosition parses the description of a CLOSEDPOSITION record, which is
+// "<SPREADBET or CFD> OPENED <opening date as YYYY-MM-DD>"
+func ParseClosedPosition(desc string) (*ClosedPositionDetails, error) {
+	fields := strings.Fields(strings.ToUpper(desc))
+	if len(fields) != 3 || fields[1] != "OPENED" {
+		return nil, fmt.Errorf("invalid closed position %q, want <SPREADBET or CFD> OPENED <opening date>", desc)
+	}
+	res := &ClosedPositionDetails{Instrument: AssetType(fields[0])}
+	if res.Instrument != SPREAD_BET_ASSET && res.Instrument != CFD_ASSET {
+		return nil, fmt.Errorf("invalid instrument %s of closed position, want %s or %s", fields[0], SPREAD_BET_ASSET, CFD_ASSET)
+	}
+	var err error
+	res.Opened, err = time.Parse("2006-01-02", fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse opening date %s: %v", fields[2], err)
+	}
+	return res, nil
+}
+
+func (d *ClosedPositionDetails) String() string {
+	return fmt.Sprintf("%s OPENED %s", d.Instrument, d.Opened.Format("2006-01-02"))
+}
+
+// ExerciseDetails stores the details of an EXERCISE record parsed from its description
+type ExerciseDetails struct {
+	Underlying string
+	// Call is true for a call option, and false for a put option
+	Call bool
+	// Assigned is true for a short position assigned, and false for a long position exercised
+	Assigned bool
+}
+
+// ParseExercise parses the description of an EXERCISE record, which is
+// "<ticker of the underlying> <CALL or PUT> <EXERCISED or ASSIGNED>"
+func ParseExercise(desc string) (*ExerciseDetails, error) {
+	fields := strings.Fields(desc)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("invalid exercise %q, want <underlying> <CALL or PUT> <EXERCISED or ASSIGNED>", desc)
+	}
+	res := &ExerciseDetails{Underlying: fields[0]}
+	switch strings.ToUpper(fields[1]) {
+	case "CALL":
+		res.Call = true
+	case "PUT":
+	default:
+		return nil, fmt.Errorf("invalid option right %s of exercise, want CALL or PUT", fields[1])
+	}
+	switch strings.ToUpper(fields[2]) {
+	case "ASSIGNED":
+		res.Assigned = true
+	case "EXERCISED":
+	default:
+		return nil, fmt.Errorf("invalid exercise %s, want EXERCISED or ASSIGNED", fields[2])
+	}
+	return res, nil
+}
+
+// Trade returns the action of the trade of the underlying delivered, which is a BUY for a call exercised
+// or a put assigned, and a SELL for a put exercised or a call assigned
+func (d *ExerciseDetails) Trade() TransactionType {
+	if d.Call != d.Assigned {
+		return Buy
+	}
+	return Sell
+}
+
+func (d *ExerciseDetails) String() string {
+	right, how := "PUT", "EXERCISED"
+	if d.Call {
+		right = "CALL"
+	}
+	if d.Assigned {
+		how = "ASSIGNED"
+	}
+	return fmt.Sprintf("%s %s %s", d.Underlying, right, how)
+}
@@ -44,20 +44,95 @@ const (
 	// Dividend
 	Dividend
 	WitholdingTax
+	// ExcessReportableIncome (ERI) of an offshore reporting fund, taxable as a dividend. It is not
+	// paid out but adds to the cost of the holding. Quantity is the number of shares held at the
+	// reporting date and Price is the ERI per share.
+	ExcessReportableIncome
+	// CostAdjustment is cash received which is a return of capital, like an equalisation payment
+	// or a capital distribution. Quantity is the cash received, see ParseCostAdjustment for
+	// the Description.
+	CostAdjustment
+	// Takeover exchanges the shares of a ticker for shares of a new ticker and/or cash,
+	// see ParseTakeover for the Description.
+	Takeover
+	// Demerger gives shares of a new ticker to the holders of a ticker, which keep their shares,
+	// see ParseDemerger for the Description.
+	Demerger
+	// RightsIssue is shares bought in a rights issue or open offer. They are added to the pool as if
+	// acquired with the shares already held, so they are never matched against a disposal like a Buy.
+	RightsIssue
+	// ScripDividend is shares received instead of a cash dividend. Price is the cash alternative per
+	// share, which is dividend income and the cost of the shares. The shares are acquired on the day
+	// like a Buy, so they are matched against the disposals of the day and the previous 30 days.
+	ScripDividend
+	// CashInLieu is cash received for the fractions of a share left over by a corporate action.
+	// Quantity is the fraction of a share disposed and Price is the cash per share.
+	CashInLieu
+	// SpouseTransferOut moves shares to the account in the Description, which belongs to the spouse or
+	// civil partner of the owner. It is a no gain, no loss disposal, so the shares move at the cost in the pool.
+	SpouseTransferOut
+	// SpouseTransferIn receives the shares of a SpouseTransferOut, with the cost in the pool of the spouse.
+	// It is generated from the SpouseTransferOut, and Description is the account the shares came from.
+	SpouseTransferIn
+	// NegligibleValue is a claim that the shares of a ticker became of negligible value, like after the
+	// company went into administration. They are deemed disposed and reacquired at the value in the
+	// Description, see ParseNegligibleValue, which crystallises the loss.
+	NegligibleValue
+	// ShareSchemeIncome is employment income from shares acquired through an employee share scheme, which is
+	// taxed through PAYE instead of CGT. Quantity is the number of shares acquired before any withheld for tax,
+	// Price is the income per share, see ParseShareScheme for the Description. The shares kept are a separate BUY.
+	ShareSchemeIncome
+	// ESPPPurchase is shares bought through an employee share purchase plan at a discount to the market value.
+	// Price is the market value per share on the purchase date, which is the cost of the shares, and the discount
+	// is employment income, see ParseESPP for the Description.
+	ESPPPurchase
+	// Interest paid on a bond or on cash, which is taxable as savings income. Quantity is the cash received.
+	Interest
+	// ClosedPosition is the profit or loss of a closed spread bet or CFD position, which is taxed on its own
+	// instead of pooled. Quantity is the size, Price is the closing level and Total is the profit in GBP,
+	// negative for a loss. See ParseClosedPosition for the Description.
+	ClosedPosition
+	// Exercise closes an option position by exercise or assignment into the underlying, whose trade on the
+	// same day is adjusted by the premium of the option. Quantity is the number of contracts, see
+	// ParseExercise for the Description.
+	Exercise
+	// PensionContribution is cash paid into a SIPP by the owner, net of the basic rate tax relief.
+	// Quantity is the cash paid in.
+	PensionContribution
+	// TaxRelief is the basic rate tax relief on a pension contribution, which the provider claims from HMRC
+	// and adds to the SIPP. Quantity is the cash added.
+	TaxRelief
 )
 
 // TransactionOrder - On a single day, this is the order the records need to be sorted by
 var TransactionOrder = map[TransactionType]int{
-	Rename:        0,
-	Split:         1,
-	TransferOut:   2,
-	TransferIn:    3,
-	CashIn:        4,
-	Dividend:      5,
-	WitholdingTax: 6,
-	Sell:          7,
-	Buy:           8,
-	CashOut:       9,
+	Rename:                 0,
+	Split:                  1,
+	Takeover:               2,
+	Demerger:               3,
+	TransferOut:            4,
+	TransferIn:             5,
+	SpouseTransferOut:      6,
+	SpouseTransferIn:       7,
+	CashIn:                 8,
+	PensionContribution:    9,
+	TaxRelief:              10,
+	Dividend:               11,
+	WitholdingTax:          12,
+	Interest:               13,
+	ExcessReportableIncome: 14,
+	CostAdjustment:         15,
+	ScripDividend:          16,
+	RightsIssue:            17,
+	CashInLieu:             18,
+	NegligibleValue:        19,
+	ShareSchemeIncome:      20,
+	Sell:                   21,
+	Buy:                    22,
+	ESPPPurchase:           23,
+	CashOut:                24,
+	ClosedPosition:         25,
+	Exercise:               26,
 }
 
 func (t TransactionType) String() string {
@@ -82,6 +157,40 @@ func (t TransactionType) String() string {
 		return "DIVIDEND"
 	case WitholdingTax:
 		return "WITHOLDINGTAX"
+	case ExcessReportableIncome:
+		return "ERI"
+	case CostAdjustment:
+		return "COSTADJUSTMENT"
+	case Takeover:
+		return "TAKEOVER"
+	case Demerger:
+		return "DEMERGER"
+	case RightsIssue:
+		return "RIGHTSISSUE"
+	case ScripDividend:
+		return "SCRIPDIVIDEND"
+	case CashInLieu:
+		return "CASHINLIEU"
+	case NegligibleValue:
+		return "NEGLIGIBLEVALUE"
+	case SpouseTransferOut:
+		return "SPOUSETRANSFEROUT"
+	case SpouseTransferIn:
+		return "SPOUSETRANSFERIN"
+	case ShareSchemeIncome:
+		return "SHARESCHEMEINCOME"
+	case ESPPPurchase:
+		return "ESPP"
+	case Interest:
+		return "INTEREST"
+	case ClosedPosition:
+		return "CLOSEDPOSITION"
+	case Exercise:
+		return "EXERCISE"
+	case PensionContribution:
+		return "PENSIONCONTRIBUTION"
+	case TaxRelief:
+		return "TAXRELIEF"
 	}
 	return ""
 }
@@ -109,16 +218,50 @@ func NewTransactionType(s string) TransactionType {
 		return Dividend
 	case "WITHOLDINGTAX":
 		return WitholdingTax
+	case "ERI":
+		return ExcessReportableIncome
+	case "COSTADJUSTMENT":
+		return CostAdjustment
+	case "TAKEOVER":
+		return Takeover
+	case "DEMERGER":
+		return Demerger
+	case "RIGHTSISSUE":
+		return RightsIssue
+	case "SCRIPDIVIDEND":
+		return ScripDividend
+	case "CASHINLIEU":
+		return CashInLieu
+	case "NEGLIGIBLEVALUE":
+		return NegligibleValue
+	case "SPOUSETRANSFEROUT":
+		return SpouseTransferOut
+	case "SPOUSETRANSFERIN":
+		return SpouseTransferIn
+	case "SHARESCHEMEINCOME":
+		return ShareSchemeIncome
+	case "ESPP":
+		return ESPPPurchase
+	case "INTEREST":
+		return Interest
+	case "CLOSEDPOSITION":
+		return ClosedPosition
+	case "EXERCISE":
+		return Exercise
+	case "PENSIONCONTRIBUTION":
+		return PensionContribution
+	case "TAXRELIEF":
+		return TaxRelief
 	}
 	return Unknown
 }
 
 func (t TransactionType) IsMetadataEvent() bool {
-	return t == Split || t == Rename
+	return t == Split || t == Rename || t == Takeover || t == Demerger || t == NegligibleValue
 }
 
 func (t TransactionType) IsCashEvent() bool {
-	return t == CashIn || t == CashOut
+	return t == CashIn || t == CashOut || t == PensionContribution || t == TaxRelief
 }
 
 func (t TransactionType) IsUnknown() bool {
@@ -129,6 +272,10 @@ func (t TransactionType) IsDividend() bool {
 	return t == Dividend || t == WitholdingTax
 }
 
+func (t TransactionType) IsIncome() bool {
+	return t == Dividend || t == ExcessReportableIncome || t == ScripDividend || t == Interest
+}
+
 // InverseAction returns the inverse of buy and sell
 func InverseAction(t TransactionType) TransactionType {
 	switch t {
@@ -181,8 +328,41 @@ const (
 	EQUITY_ASSET  AssetType = "EQUITY"
 	FOREX_ASSET   AssetType = "FOREX"
 	ETF_ASSET     AssetType = "ETF"
+	// CRYPTO_ASSET is a cryptoasset like BTC, which is always recorded in GBP as its ticker is the currency
+	CRYPTO_ASSET AssetType = "CRYPTO"
+	// GILT_ASSET is a UK government bond and QCB_ASSET is a qualifying corporate bond. Both are exempt
+	// from CGT and quoted as the clean price per 100 of nominal.
+	GILT_ASSET AssetType = "GILT"
+	QCB_ASSET  AssetType = "QCB"
+	// SPREAD_BET_ASSET and CFD_ASSET are the markets of closed positions, which are never held or quoted
+	SPREAD_BET_ASSET AssetType = "SPREADBET"
+	CFD_ASSET        AssetType = "CFD"
+	// OPTION_ASSET and FUTURE_ASSET are listed contracts, whose trades are matched against the opening of
+	// the position instead of pooled. The price is per unit of the underlying, see Record.Multiplier.
+	OPTION_ASSET AssetType = "OPTION"
+	FUTURE_ASSET AssetType = "FUTURE"
 )
 
+// NewAssetType returns the asset type in the string, which can be empty if unknown
+func NewAssetType(s string) (AssetType, error) {
+	switch a := AssetType(strings.ToUpper(strings.TrimSpace(s))); a {
+	case UNKNOWN_ASSET, EQUITY_ASSET, FOREX_ASSET, ETF_ASSET, CRYPTO_ASSET, GILT_ASSET, QCB_ASSET,
+		SPREAD_BET_ASSET, CFD_ASSET, OPTION_ASSET, FUTURE_ASSET:
+		return a, nil
+	}
+	return "", fmt.Errorf("invalid asset type %q", s)
+}
+
+// IsBond returns true for the bonds which are exempt from CGT
+func (a AssetType) IsBond() bool {
+	return a == GILT_ASSET || a == QCB_ASSET
+}
+
+// IsDerivative returns true for the contracts which have no market data of their own
+func (a AssetType) IsDerivative() bool {
+	return a == SPREAD_BET_ASSET || a == CFD_ASSET || a == OPTION_ASSET || a == FUTURE_ASSET
+}
+
 // Account stores information about the account aka broker where something happened
 type Account struct {
 	// If Name is set to "*", it implies a global event like a stock split or rename of ticker.
@@ -192,6 +372,45 @@ type Account struct {
 	// If CGTExempt is true, then transactions in this account are exempt from CGT calculation,
 	// but you can still see the profit/loss for it.
 	CGTExempt bool
+	// Owner of the account, empty for a single person. CGT is calculated separately for every owner.
+	Owner string
+	// If PersonalUseFX is true, then the foreign currency in this account is for personal use and
+	// exempt from CGT, while the rest of the account is not.
+	PersonalUseFX bool
+	// Type of the account, like an ISA
+	Type AccountType
+}
+
+// AccountType is the tax wrapper of an account
+type AccountType string
+
+const (
+	// GIA_ACCOUNT is a general investment account, which is not a tax wrapper
+	GIA_ACCOUNT  AccountType = "GIA"
+	ISA_ACCOUNT  AccountType = "ISA"
+	LISA_ACCOUNT AccountType = "LISA"
+	SIPP_ACCOUNT AccountType = "SIPP"
+)
+
+// NewAccountType returns the account type from its name, which is a GIA if empty
+func NewAccountType(s string) (AccountType, error) {
+	switch t := AccountType(strings.ToUpper(strings.TrimSpace(s))); t {
+	case "":
+		return GIA_ACCOUNT, nil
+	case GIA_ACCOUNT, ISA_ACCOUNT, LISA_ACCOUNT, SIPP_ACCOUNT:
+		return t, nil
+	}
+	return "", fmt.Errorf("invalid account type %q, want one of GIA, ISA, LISA or SIPP", s)
+}
+
+// IsWrapper returns true for the tax free wrappers, which are exempt from CGT
+func (t AccountType) IsWrapper() bool {
+	return t == ISA_ACCOUNT || t == LISA_ACCOUNT || t == SIPP_ACCOUNT
+}
+
+// IsISA returns true for the accounts whose subscriptions count towards the annual ISA allowance
+func (t AccountType) IsISA() bool {
+	return t == ISA_ACCOUNT || t == LISA_ACCOUNT
 }
 
 func AccountFromProto(act *statementspb.Account) (Account, error) {
@@ -202,10 +421,17 @@ func AccountFromProto(act *statementspb.Account) (Account, error) {
 	if curr == "" {
 		return Account{}, fmt.Errorf("invalid currency: %q", act.GetCurrency())
 	}
+	actType, err := NewAccountType(act.GetType())
+	if err != nil {
+		return Account{}, err
+	}
 	return Account{
-		Name:      act.GetName(),
-		Currency:  curr,
-		CGTExempt: act.GetCgtExempt(),
+		Name:          act.GetName(),
+		Currency:      curr,
+		CGTExempt:     act.GetCgtExempt() || actType.IsWrapper(),
+		Owner:         act.GetOwner(),
+		PersonalUseFX: act.GetPersonalUseFx(),
+		Type:          actType,
 	}, nil
 }
 
@@ -223,6 +449,23 @@ type Record struct {
 	Commission    float64         `csv:"Commission"`   // Commission is always in GBP
 	Total         float64         `csv:"Total"`        // Total is always in GBP
 	Description   string          `csv:"Description"`  // used for rename and split types
+	// Multiplier is the quantity the price is for, like 0.01 for a bond priced per 100 of nominal
+	// or 100 for an option contract on 100 shares. 0 is the same as 1.
+	Multiplier float64 `csv:"Multiplier"`
+	// AccruedInterest is the interest in GBP accrued on a bond since the last coupon, which the buyer
+	// pays to the seller on top of the total. It is income of the seller under the accrued income scheme.
+	AccruedInterest float64 `csv:"AccruedInterest"`
+	// AssetType is the type of the ticker if known from the input, which is set in the db when the
+	// record is enriched. It is needed for the tickers like gilts which are not equities.
+	AssetType AssetType `csv:"AssetType"`
+}
+
+// PriceMultiplier returns the multiplier of the price, which is 1 if not set
+func (r *Record) PriceMultiplier() float64 {
+	if r.Multiplier == 0.0 {
+		return 1.0
+	}
+	return r.Multiplier
 }
 
 func (r *Record) String() string {
@@ -253,6 +496,12 @@ func (r *Record) Header() []string {
 		"Commission",
 		"Total",
 		"Description",
+		"Account.Owner",
+		"Account.PersonalUseFX",
+		"Multiplier",
+		"AccruedInterest",
+		"Account.Type",
+		"AssetType",
 	}
 }
 
@@ -273,15 +522,25 @@ func (r *Record) MarshalCSV() []string {
 		fmt.Sprintf("%f", r.Commission),
 		fmt.Sprintf("%f", r.Total),
 		r.Description,
+		r.Broker.Owner,
+		fmt.Sprintf("%t", r.Broker.PersonalUseFX),
+		fmt.Sprintf("%f", r.Multiplier),
+		fmt.Sprintf("%f", r.AccruedInterest),
+		string(r.Broker.Type),
+		string(r.AssetType),
 	}
 }
 
 func (r *Record) AssertMaths() error {
-	want := (r.ShareCount * r.PricePerShare * r.ExchangeRate)
+	// the profit of a closed position depends on the opening level as well
+	if r.Action == ClosedPosition {
+		return nil
+	}
+	want := (r.ShareCount * r.PricePerShare * r.PriceMultiplier() * r.ExchangeRate)
 	switch r.Action {
-	case Buy:
+	case Buy, RightsIssue, ESPPPurchase:
 		want += r.Commission
-	case Sell:
+	case Sell, CashInLieu:
 		want -= r.Commission
 	}
 	if math.Abs(want-r.Total) > 0.1 {
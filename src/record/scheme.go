@@ -0,0 +1,79 @@
+package record
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ShareSchemeDetails stores the details of a SHARESCHEMEINCOME record parsed from its description
+type ShareSchemeDetails struct {
+	// Scheme the shares are acquired through, like RSU
+	Scheme string
+	// Withheld is the number of shares withheld or sold by the employer to pay the income tax and NIC
+	Withheld float64
+}
+
+// ParseShareScheme parses the description of a SHARESCHEMEINCOME record, which is
+// "<scheme>" optionally followed by "WITHHELD <shares withheld for tax>"
+func ParseShareScheme(desc string) (*ShareSchemeDetails, error) {
+	fields := strings.Fields(strings.ToUpper(desc))
+	if len(fields) != 1 && (len(fields) != 3 || fields[1] != "WITHHELD") {
+		return nil, fmt.Errorf("invalid share scheme %q, want <scheme> [WITHHELD <shares>]", desc)
+	}
+	res := &ShareSchemeDetails{Scheme: fields[0]}
+	if len(fields) == 1 {
+		return res, nil
+	}
+	var err error
+	res.Withheld, err = strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %v to shares withheld as float: %v", fields[2], err)
+	}
+	if res.Withheld < 0.0 {
+		return nil, fmt.Errorf("shares withheld cannot be negative: %q", desc)
+	}
+	return res, nil
+}
+
+func (d *ShareSchemeDetails) String() string {
+	if d.Withheld == 0.0 {
+		return d.Scheme
+	}
+	return fmt.Sprintf("%s WITHHELD %f", d.Scheme, d.Withheld)
+}
+
+// ESPPDetails stores the details of an ESPP record parsed from its description
+type ESPPDetails struct {
+	// PurchasePrice is the discounted price per share paid, in the currency of the record
+	PurchasePrice float64
+	// OfferingDate is the start of the offering period, zero if not known
+	OfferingDate time.Time
+}
+
+// ParseESPP parses the description of an ESPP record, which is "<purchase price per share>"
+// optionally followed by "OFFERED <offering date as YYYY-MM-DD>"
+func ParseESPP(desc string) (*ESPPDetails, error) {
+	fields := strings.Fields(strings.ToUpper(desc))
+	if len(fields) != 1 && (len(fields) != 3 || fields[1] != "OFFERED") {
+		return nil, fmt.Errorf("invalid ESPP purchase %q, want <purchase price> [OFFERED <offering date>]", desc)
+	}
+	res := &ESPPDetails{}
+	var err error
+	res.PurchasePrice, err = strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %v to purchase price as float: %v", fields[0], err)
+	}
+	if res.PurchasePrice < 0.0 {
+		return nil, fmt.Errorf("purchase price cannot be negative: %q", desc)
+	}
+	if len(fields) == 1 {
+		return res, nil
+	}
+	res.OfferingDate, err = time.Parse("2006-01-02", fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse offering date %s: %v", fields[2], err)
+	}
+	return res, nil
+}
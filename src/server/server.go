@@ -3,11 +3,14 @@ package server
 import (
 	"crypto/sha256"
 	"crypto/subtle"
+	"encoding/json"
 	"fmt"
+	"html"
 	"net/http"
 	"os"
 	"path"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,6 +19,7 @@ import (
 	"aagr.xyz/trades/marketdata"
 	"aagr.xyz/trades/record"
 	"aagr.xyz/trades/statements"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"golang.org/x/exp/maps"
 
 	log "github.com/sirupsen/logrus"
@@ -44,13 +48,18 @@ type Config struct {
 	Auth       *Authorization
 	Static     *StaticLoader
 	Market     *marketdata.Service
+	// TaxYears stores the personal details for each tax year, keyed by owner and then by name of tax year
+	TaxYears map[string]map[string]*holdings.TaxYearConfig
 }
 
 type Server struct {
 	config    *Config
 	records   []*record.Record
 	byAccount map[record.Account]*holdings.Account
-	byTicker  map[string]*holdings.Holding
+	// byOwner stores the holdings of every owner, which are used for CGT
+	byOwner map[string]map[string]*holdings.Holding
+	// byTicker stores the holdings of all the owners together, which are used for the portfolio
+	byTicker map[string]*holdings.Holding
 }
 
 func New(cfg *Config) (*Server, error) {
@@ -70,6 +79,7 @@ func New(cfg *Config) (*Server, error) {
 		config:    cfg,
 		records:   records,
 		byAccount: make(map[record.Account]*holdings.Account),
+		byOwner:   make(map[string]map[string]*holdings.Holding),
 		byTicker:  make(map[string]*holdings.Holding),
 	}, nil
 }
@@ -101,11 +111,20 @@ func (s *Server) Run(port int) error {
 	}
 	http.HandleFunc("/healthz", s.healthz)
 	http.HandleFunc("/portfolio", s.basicAuth(s.portfolioHandler))
+	http.HandleFunc("/harvest", s.basicAuth(s.harvestHandler))
 	http.HandleFunc("/accounts", s.basicAuth(s.accountHandler))
 	http.HandleFunc("/cgt", s.basicAuth(s.cgtHandler))
+	http.HandleFunc("/income", s.basicAuth(s.incomeHandler))
+	http.HandleFunc("/isa", s.basicAuth(s.isaHandler))
 	http.HandleFunc("/csv/portfolio", s.basicAuth(s.portfolioCSVHandler))
 	http.HandleFunc("/csv/accounts", s.basicAuth(s.accountsCSVHandler))
 	http.HandleFunc("/csv/transactions", s.basicAuth(s.transactionsHandler))
+	http.HandleFunc("/sa108", s.basicAuth(s.sa108Handler))
+	http.HandleFunc("/csv/sa108", s.basicAuth(s.sa108CSVHandler))
+	http.HandleFunc("/json/sa108", s.basicAuth(s.sa108JSONHandler))
+	http.HandleFunc("/csv/disposals", s.basicAuth(s.disposalsCSVHandler))
+	http.HandleFunc("/simulate", s.basicAuth(s.simulateHandler))
+	http.HandleFunc("/json/disposals", s.basicAuth(s.disposalsJSONHandler))
 	http.HandleFunc("/quit/quit/quit", s.basicAuth(s.quit))
 	if port <= 0 {
 		return nil
@@ -116,17 +135,57 @@ func (s *Server) Run(port int) error {
 
 func (s *Server) Update() error {
 	var err error
-	s.byAccount, err = holdings.ByAccount(s.records)
+	var transfers []*record.Record
+	s.byOwner, transfers, err = holdings.ByOwner(s.records)
 	if err != nil {
-		return fmt.Errorf("cannot get holdings by accounts: %v", err)
+		return fmt.Errorf("Cannot compute holdings by owner: %v", err)
 	}
-	s.byTicker, err = holdings.ByTicker(s.records)
+	s.byAccount, err = holdings.ByAccount(s.records, transfers)
 	if err != nil {
-		return fmt.Errorf("Cannot compute holdings by ticker: %v", err)
+		return fmt.Errorf("cannot get holdings by accounts: %v", err)
 	}
+	s.byTicker = holdings.Merge(s.byOwner)
 	return nil
 }
 
+// owners returns the owners of the accounts, sorted
+func (s *Server) owners() []string {
+	owners := maps.Keys(s.byOwner)
+	sort.Strings(owners)
+	return owners
+}
+
+// ownerTitle returns the heading for the CGT sections of an owner, which is empty for a single person
+func (s *Server) ownerTitle(owner string) string {
+	if owner == "" && len(s.byOwner) <= 1 {
+		return ""
+	}
+	if owner == "" {
+		return "Owner: (none)"
+	}
+	return fmt.Sprintf("Owner: %s", owner)
+}
+
+// ownerFromRequest returns the owner in the "owner" query parameter, which can be left out
+// if there is only one owner
+func (s *Server) ownerFromRequest(r *http.Request) (string, error) {
+	q := r.URL.Query()
+	if q.Has("owner") {
+		owner := q.Get("owner")
+		if _, ok := s.byOwner[owner]; !ok {
+			return "", fmt.Errorf("owner %q not found, want one of %q", owner, s.owners())
+		}
+		return owner, nil
+	}
+	if len(s.byOwner) > 1 {
+		return "", fmt.Errorf("owner query parameter is needed, want one of %q", s.owners())
+	}
+	for owner := range s.byOwner {
+		return owner, nil
+	}
+	return "", nil
+}
+
 func (s *Server) quit(w http.ResponseWriter, r *http.Request) {
 	os.Exit(-1)
 }
@@ -191,6 +250,54 @@ func (s *Server) portfolioHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "cannot generate portfolio", http.StatusInternalServerError)
 	}
 }
+func (s *Server) harvestHandler(w http.ResponseWriter, r *http.Request) {
+	owner, err := s.ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	accounts := make(map[record.Account]*holdings.Account)
+	for act, a := range s.byAccount {
+		if act.Owner == owner {
+			accounts[act] = a
+		}
+	}
+	plan, err := holdings.Harvest(s.byOwner[owner], s.config.TaxYears[owner], accounts, s.config.Market)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("cannot generate harvest plan: %v", err), http.StatusInternalServerError)
+		return
+	}
+	tmpl := s.config.Static.Harvest()
+	if tmpl == nil {
+		http.Error(w, "no content", http.StatusInternalServerError)
+		return
+	}
+	type Section struct {
+		Name string
+		Rows []*holdings.HarvestRow
+	}
+	type Data struct {
+		Timestamp string
+		Owner     string
+		Plan      *holdings.HarvestPlan
+		Sections  []*Section
+	}
+	d := Data{
+		Timestamp: time.Now().Format(timeFmt),
+		Owner:     s.ownerTitle(owner),
+		Plan:      plan,
+		Sections: []*Section{
+			{Name: "Unrealised Gains", Rows: plan.Gains},
+			{Name: "Unrealised Losses", Rows: plan.Losses},
+		},
+	}
+	err = tmpl.Execute(w, d)
+	if err != nil {
+		log.Errorf("cannot execute template: %v", err)
+		http.Error(w, "cannot generate harvest plan", http.StatusInternalServerError)
+	}
+}
+
 func (s *Server) portfolioCSVHandler(w http.ResponseWriter, r *http.Request) {
 	portfolio, err := holdings.PortfolioTable(s.byTicker, s.config.Market)
 	if err != nil {
@@ -217,16 +324,267 @@ func (s *Server) cgtHandler(w http.ResponseWriter, r *http.Request) {
 		<title>CGT Calculation Report: %s</title>
 	</head>
 	<body>`, time.Now().Format(timeFmt))
-	cgt, _ := holdings.CGT(s.byTicker)
-	years := maps.Keys(cgt)
+	for _, owner := range s.owners() {
+		byTicker, configs := s.byOwner[owner], s.config.TaxYears[owner]
+		if title := s.ownerTitle(owner); title != "" {
+			fmt.Fprintf(w, "<h2>%s</h2>", html.EscapeString(title))
+		}
+		cgt, fx := holdings.CGT(byTicker), holdings.FXTables(byTicker)
+		byYear := make(map[string][]*holdings.Disposal)
+		for _, d := range holdings.Disposals(byTicker) {
+			byYear[d.TaxYear] = append(byYear[d.TaxYear], d)
+		}
+		years := maps.Keys(cgt)
+		sort.Strings(years)
+		for _, y := range years {
+			fmt.Fprint(w, cgt[y].RenderHTML())
+			if t, ok := fx[y]; ok {
+				fmt.Fprint(w, t.RenderHTML())
+			}
+			// Drill down into how the gain of every disposal was calculated
+			for _, d := range byYear[y] {
+				fmt.Fprintf(w, "<details><summary>%s</summary>%s</details>",
+					html.EscapeString(d.String()), holdings.MatchTable(d).RenderHTML())
+			}
+			fmt.Fprint(w, "<br><br>")
+		}
+		fmt.Fprint(w, holdings.LiabilityTable(byTicker, configs).RenderHTML())
+		fmt.Fprint(w, "<br><br>")
+		fmt.Fprint(w, holdings.ReconciliationTable(byTicker).RenderHTML())
+		fmt.Fprint(w, "<br><br>")
+		if periods := holdings.RatePeriodTable(byTicker, configs); periods.Length() > 0 {
+			fmt.Fprint(w, periods.RenderHTML())
+			fmt.Fprint(w, "<br><br>")
+		}
+		fmt.Fprint(w, holdings.LossTable(byTicker, configs).RenderHTML())
+		fmt.Fprint(w, "<br><br>")
+		// Share scheme income is taxed through PAYE, but shown here as it sets the cost of the shares
+		schemes := holdings.ShareSchemeTables(s.records, owner)
+		years = maps.Keys(schemes)
+		sort.Strings(years)
+		for _, y := range years {
+			fmt.Fprint(w, schemes[y].RenderHTML())
+			fmt.Fprint(w, "<br><br>")
+		}
+		// Spread bets are exempt, so they are listed apart from the gains
+		spreadBets := holdings.SpreadBetTables(byTicker)
+		years = maps.Keys(spreadBets)
+		sort.Strings(years)
+		for _, y := range years {
+			fmt.Fprint(w, spreadBets[y].RenderHTML())
+			fmt.Fprint(w, "<br><br>")
+		}
+	}
+	fmt.Fprint(w, `</body></html>`)
+}
+
+func (s *Server) incomeHandler(w http.ResponseWriter, r *http.Request) {
+	owner, err := s.ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fmt.Fprintf(w, `
+	<!DOCTYPE html>
+	<html>
+	<head>
+		<title>Income Report: %s</title>
+	</head>
+	<body>`, time.Now().Format(timeFmt))
+	if title := s.ownerTitle(owner); title != "" {
+		fmt.Fprintf(w, "<h2>%s</h2>", html.EscapeString(title))
+	}
+	income := holdings.IncomeTables(s.records, owner)
+	years := maps.Keys(income)
 	sort.Strings(years)
 	for _, y := range years {
-		fmt.Fprint(w, cgt[y].RenderHTML())
+		fmt.Fprint(w, income[y].RenderHTML())
 		fmt.Fprint(w, "<br><br>")
 	}
 	fmt.Fprint(w, `</body></html>`)
 }
 
+func (s *Server) isaHandler(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, `
+	<!DOCTYPE html>
+	<html>
+	<head>
+		<title>ISA Subscriptions: %s</title>
+	</head>
+	<body>`, time.Now().Format(timeFmt))
+	for _, owner := range s.owners() {
+		if title := s.ownerTitle(owner); title != "" {
+			fmt.Fprintf(w, "<h2>%s</h2>", html.EscapeString(title))
+		}
+		isa := holdings.ISATables(s.records, owner)
+		years := maps.Keys(isa)
+		sort.Strings(years)
+		for _, y := range years {
+			fmt.Fprint(w, isa[y].RenderHTML())
+			fmt.Fprint(w, "<br><br>")
+		}
+	}
+	fmt.Fprint(w, `</body></html>`)
+}
+
+// sa108Table returns the SA108 table of the owner, with the owner in the title
+func (s *Server) sa108Table(owner string) table.Writer {
+	t := holdings.SA108Table(holdings.SA108Reports(s.byOwner[owner], s.config.TaxYears[owner]))
+	if title := s.ownerTitle(owner); title != "" {
+		t.SetTitle(fmt.Sprintf("SA108 Capital Gains Summary - %s", title))
+	}
+	return t
+}
+
+func (s *Server) sa108Handler(w http.ResponseWriter, r *http.Request) {
+	for _, owner := range s.owners() {
+		fmt.Fprintf(w, "%s\n\n", s.sa108Table(owner).Render())
+	}
+}
+
+func (s *Server) sa108CSVHandler(w http.ResponseWriter, r *http.Request) {
+	owner, err := s.ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	t := s.sa108Table(owner)
+	t.SetTitle("")
+	fmt.Fprint(w, t.RenderCSV())
+}
+
+func (s *Server) sa108JSONHandler(w http.ResponseWriter, r *http.Request) {
+	owner, err := s.ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	reports := holdings.SA108Reports(s.byOwner[owner], s.config.TaxYears[owner])
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(reports); err != nil {
+		log.Errorf("cannot encode SA108 reports: %v", err)
+		http.Error(w, "cannot generate SA108 reports", http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) disposalsCSVHandler(w http.ResponseWriter, r *http.Request) {
+	owner, err := s.ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	t := holdings.DisposalTable(holdings.Disposals(s.byOwner[owner]))
+	t.SetTitle("")
+	fmt.Fprint(w, t.RenderCSV())
+}
+
+func (s *Server) disposalsJSONHandler(w http.ResponseWriter, r *http.Request) {
+	owner, err := s.ownerFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(holdings.Disposals(s.byOwner[owner])); err != nil {
+		log.Errorf("cannot encode disposals: %v", err)
+		http.Error(w, "cannot generate disposals", http.StatusInternalServerError)
+	}
+}
+
+// Simulate returns the outcome of selling quantity of ticker at price in the account today.
+// If price is not positive, the present market price is used.
+func (s *Server) Simulate(ticker, account string, quantity, price float64) (*holdings.Simulation, error) {
+	if quantity <= 0.0 {
+		return nil, fmt.Errorf("quantity to sell must be positive, got %f", quantity)
+	}
+	var act *record.Account
+	for a := range s.byAccount {
+		if strings.EqualFold(a.Name, account) {
+			act = &a
+			break
+		}
+	}
+	if act == nil {
+		return nil, fmt.Errorf("account %q not found", account)
+	}
+	meta, err := db.TickerMeta(ticker)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get ticker metadata for %s: %v", ticker, err)
+	}
+	if price <= 0.0 {
+		quote, err := s.config.Market.GetQuote(ticker, meta.Currency, meta.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get quote for %s: %v", ticker, err)
+		}
+		price = quote.RegularMarketPrice
+	}
+	rate, err := s.config.Market.GetForex(meta.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get exchange rate for %s: %v", meta.Currency, err)
+	}
+	sell := &record.Record{
+		Timestamp:     time.Now(),
+		Broker:        *act,
+		Action:        record.Sell,
+		Ticker:        ticker,
+		Name:          ticker,
+		ShareCount:    quantity,
+		PricePerShare: price,
+		Currency:      meta.Currency,
+		ExchangeRate:  rate,
+		Total:         quantity * price * rate,
+		Description:   "simulated",
+	}
+	return holdings.Simulate(s.byOwner[act.Owner], sell)
+}
+
+func (s *Server) simulateHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	fmt.Fprint(w, `
+	<!DOCTYPE html>
+	<html>
+	<head>
+		<title>Simulate a SELL</title>
+	</head>
+	<body>`)
+	fmt.Fprintf(w, `
+	<form method="get" action="/simulate">
+		<label>Ticker <input name="ticker" value="%s"></label>
+		<label>Quantity <input name="quantity" value="%s"></label>
+		<label>Price <input name="price" value="%s" placeholder="present price"></label>
+		<label>Account <input name="account" value="%s"></label>
+		<input type="submit" value="Simulate">
+	</form><br>`,
+		html.EscapeString(q.Get("ticker")), html.EscapeString(q.Get("quantity")),
+		html.EscapeString(q.Get("price")), html.EscapeString(q.Get("account")))
+	defer fmt.Fprint(w, `</body></html>`)
+	if q.Get("ticker") == "" {
+		return
+	}
+	quantity, err := strconv.ParseFloat(q.Get("quantity"), 64)
+	if err != nil {
+		fmt.Fprintf(w, "<p>Invalid quantity: %s</p>", html.EscapeString(err.Error()))
+		return
+	}
+	var price float64
+	if q.Get("price") != "" {
+		if price, err = strconv.ParseFloat(q.Get("price"), 64); err != nil {
+			fmt.Fprintf(w, "<p>Invalid price: %s</p>", html.EscapeString(err.Error()))
+			return
+		}
+	}
+	sim, err := s.Simulate(q.Get("ticker"), q.Get("account"), quantity, price)
+	if err != nil {
+		fmt.Fprintf(w, "<p>Cannot simulate the SELL: %s</p>", html.EscapeString(err.Error()))
+		return
+	}
+	fmt.Fprint(w, holdings.SimulationTable(sim).RenderHTML())
+	if sim.Disposal != nil {
+		fmt.Fprint(w, "<br>")
+		fmt.Fprint(w, holdings.MatchTable(sim.Disposal).RenderHTML())
+	}
+}
+
 func (s *Server) accountHandler(w http.ResponseWriter, r *http.Request) {
 	byAct, err := holdings.AccountRows(s.byAccount, s.config.Market)
 	if err != nil {
@@ -247,6 +605,10 @@ func (s *Server) accountHandler(w http.ResponseWriter, r *http.Request) {
 	type Data struct {
 		Timestamp string
 		Accounts  []*AccountData
+		// Pensions is the valuation of the SIPPs, and PensionAllowances are the HTML tables of the
+		// annual allowance of every owner
+		Pensions          []*holdings.PensionRow
+		PensionAllowances []string
 	}
 
 	d := Data{Timestamp: time.Now().Format(timeFmt)}
@@ -265,6 +627,21 @@ func (s *Server) accountHandler(w http.ResponseWriter, r *http.Request) {
 		ad.TotalGainPercentage = ad.TotalGain / ad.TotalCost * 100.0
 		d.Accounts = append(d.Accounts, ad)
 	}
+	d.Pensions = holdings.PensionRows(byAct, s.records)
+	for _, owner := range s.owners() {
+		t, err := holdings.PensionAllowanceTable(s.records, owner)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("cannot generate pension allowance: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if t.Length() == 0 {
+			continue
+		}
+		if title := s.ownerTitle(owner); title != "" {
+			t.SetTitle(fmt.Sprintf("Pension Annual Allowance - %s", title))
+		}
+		d.PensionAllowances = append(d.PensionAllowances, t.RenderHTML())
+	}
 	err = tmpl.Execute(w, d)
 	if err != nil {
 		log.Errorf("cannot execute template: %v", err)
@@ -299,7 +676,6 @@ func (s *Server) writeReport(filename string) error {
 	if err != nil {
 		return fmt.Errorf("cannot generate portfolio: %v", err)
 	}
-	cgt, debug := holdings.CGT(s.byTicker)
 	accounts, err := holdings.AccountTable(s.byAccount, s.config.Market)
 	if err != nil {
 		return fmt.Errorf("cannot generate accounts: %v", err)
@@ -315,12 +691,89 @@ func (s *Server) writeReport(filename string) error {
 		sb.WriteString(fmt.Sprintf("%s\n\n", act.Render()))
 	}
 	sb.WriteString("--------- CGT Calculation Report --------\n\n")
-	years := maps.Keys(cgt)
-	sort.Strings(years)
-	for _, y := range years {
-		sb.WriteString(fmt.Sprintf("%s\n\n", cgt[y].Render()))
+	for _, owner := range s.owners() {
+		byTicker, configs := s.byOwner[owner], s.config.TaxYears[owner]
+		if title := s.ownerTitle(owner); title != "" {
+			sb.WriteString(fmt.Sprintf("%s\n\n", title))
+		}
+		cgt, fx := holdings.CGT(byTicker), holdings.FXTables(byTicker)
+		years := maps.Keys(cgt)
+		sort.Strings(years)
+		for _, y := range years {
+			sb.WriteString(fmt.Sprintf("%s\n\n", cgt[y].Render()))
+			if t, ok := fx[y]; ok {
+				sb.WriteString(fmt.Sprintf("%s\n\n", t.Render()))
+			}
+		}
+		sb.WriteString(fmt.Sprintf("%s\n\n", holdings.LiabilityTable(byTicker, configs).Render()))
+		sb.WriteString(fmt.Sprintf("%s\n\n", holdings.ReconciliationTable(byTicker).Render()))
+		if periods := holdings.RatePeriodTable(byTicker, configs); periods.Length() > 0 {
+			sb.WriteString(fmt.Sprintf("%s\n\n", periods.Render()))
+		}
+		sb.WriteString(fmt.Sprintf("%s\n\n", holdings.LossTable(byTicker, configs).Render()))
+		schemes := holdings.ShareSchemeTables(s.records, owner)
+		years = maps.Keys(schemes)
+		sort.Strings(years)
+		for _, y := range years {
+			sb.WriteString(fmt.Sprintf("%s\n\n", schemes[y].Render()))
+		}
+		spreadBets := holdings.SpreadBetTables(byTicker)
+		years = maps.Keys(spreadBets)
+		sort.Strings(years)
+		for _, y := range years {
+			sb.WriteString(fmt.Sprintf("%s\n\n", spreadBets[y].Render()))
+		}
+	}
+	sb.WriteString("--------- Income Report --------\n\n")
+	for _, owner := range s.owners() {
+		if title := s.ownerTitle(owner); title != "" {
+			sb.WriteString(fmt.Sprintf("%s\n\n", title))
+		}
+		income := holdings.IncomeTables(s.records, owner)
+		years := maps.Keys(income)
+		sort.Strings(years)
+		for _, y := range years {
+			sb.WriteString(fmt.Sprintf("%s\n\n", income[y].Render()))
+		}
+	}
+	sb.WriteString("--------- ISA Subscriptions --------\n\n")
+	for _, owner := range s.owners() {
+		if title := s.ownerTitle(owner); title != "" {
+			sb.WriteString(fmt.Sprintf("%s\n\n", title))
+		}
+		isa := holdings.ISATables(s.records, owner)
+		years := maps.Keys(isa)
+		sort.Strings(years)
+		for _, y := range years {
+			sb.WriteString(fmt.Sprintf("%s\n\n", isa[y].Render()))
+		}
+	}
+	sb.WriteString("--------- Pension Annual Allowance --------\n\n")
+	for _, owner := range s.owners() {
+		t, err := holdings.PensionAllowanceTable(s.records, owner)
+		if err != nil {
+			return fmt.Errorf("cannot generate pension allowance: %v", err)
+		}
+		if t.Length() == 0 {
+			continue
+		}
+		if title := s.ownerTitle(owner); title != "" {
+			t.SetTitle(fmt.Sprintf("Pension Annual Allowance - %s", title))
+		}
+		sb.WriteString(fmt.Sprintf("%s\n\n", t.Render()))
+	}
+	sb.WriteString("--------- SA108 --------\n\n")
+	for _, owner := range s.owners() {
+		sb.WriteString(fmt.Sprintf("%s\n\n", s.sa108Table(owner).Render()))
+	}
+	sb.WriteString("--------- CGT Disposals --------\n\n")
+	for _, owner := range s.owners() {
+		t := holdings.DisposalTable(holdings.Disposals(s.byOwner[owner]))
+		if title := s.ownerTitle(owner); title != "" {
+			t.SetTitle(fmt.Sprintf("CGT Disposals - %s", title))
+		}
+		sb.WriteString(fmt.Sprintf("%s\n\n", t.Render()))
 	}
-	sb.WriteString(fmt.Sprintf("-------------------------- DEBUG INFO ----------------\n\n%s", debug))
 	if err := os.WriteFile(filename, []byte(sb.String()), 0644); err != nil {
 		return fmt.Errorf("cannot output report: %v", err)
 	}
@@ -49,3 +49,7 @@ func (s *StaticLoader) Portfolio() *template.Template {
 func (s *StaticLoader) Accounts() *template.Template {
 	return s.templates["accounts.html"]
 }
+
+func (s *StaticLoader) Harvest() *template.Template {
+	return s.templates["harvest.html"]
+}
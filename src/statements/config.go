@@ -70,6 +70,30 @@ func parserFromProto(cfg *pb.Statement) (parser.Parser, error) {
 			return nil, fmt.Errorf("cannot parse account: %v", err)
 		}
 		return parser.NewIBKRDividend(act), nil
+	case *pb.Statement_CoinbaseParser:
+		act, err := record.AccountFromProto(pCfg.CoinbaseParser.GetAccount())
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse account: %v", err)
+		}
+		return parser.NewCoinbase(act)
+	case *pb.Statement_KrakenParser:
+		act, err := record.AccountFromProto(pCfg.KrakenParser.GetAccount())
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse account: %v", err)
+		}
+		return parser.NewKraken(act)
+	case *pb.Statement_IgSpreadBetParser:
+		act, err := record.AccountFromProto(pCfg.IgSpreadBetParser.GetAccount())
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse account: %v", err)
+		}
+		return parser.NewIGSpreadBet(act)
+	case *pb.Statement_IgCfdParser:
+		act, err := record.AccountFromProto(pCfg.IgCfdParser.GetAccount())
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse account: %v", err)
+		}
+		return parser.NewIGCFD(act)
 	}
 	return nil, fmt.Errorf("invalid type")
 }
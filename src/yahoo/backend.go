@@ -22,12 +22,15 @@ func NewBackend() (*Backend, error) {
 	return &Backend{client: main}, nil
 }
 
-func (b *Backend) GuessTicker(symbol string, currency record.Currency) (string, error) {
+func (b *Backend) GuessTicker(symbol string, currency record.Currency, assetType record.AssetType) (string, error) {
 	ticker := symbol
-	if strings.HasPrefix(string(currency), "GB") {
+	switch {
+	case assetType == record.CRYPTO_ASSET:
+		// Cryptoassets are quoted against a currency, like BTC-GBP
+		ticker = fmt.Sprintf("%s-%s", symbol, currency)
+	case strings.HasPrefix(string(currency), "GB"):
 		ticker += ".L"
-	}
-	if currency == record.CHF {
+	case currency == record.CHF:
 		ticker += ".SW"
 	}
 	_, err := getQuote(b.client, ticker)